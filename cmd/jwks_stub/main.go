@@ -0,0 +1,92 @@
+// Command jwks_stub is a local stand-in for a Cloudflare Access team domain.
+// It serves a JWKS at /cdn-cgi/access/certs and mints signed tokens at
+// /token?sub=<uuid>&email=<email>, so the server can be run and exercised
+// offline with:
+//
+//	CF_ACCESS_TEAM_DOMAIN=http://localhost:9090 CF_ACCESS_AUD=local-dev \
+//	CF_ACCESS_CERTS_URL=http://localhost:9090/cdn-cgi/access/certs
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const kid = "local-stub-key"
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "9090"
+	}
+	audience := os.Getenv("CF_ACCESS_AUD")
+	if audience == "" {
+		audience = "local-dev"
+	}
+	issuer := os.Getenv("CF_ACCESS_TEAM_DOMAIN")
+	if issuer == "" {
+		issuer = "http://localhost:" + port
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	http.HandleFunc("/cdn-cgi/access/certs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": kid,
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	http.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sub := r.URL.Query().Get("sub")
+		if sub == "" {
+			sub = uuid.New().String()
+		}
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			email = "founder@example.com"
+		}
+
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub":   sub,
+			"email": email,
+			"aud":   []string{audience},
+			"iss":   issuer,
+			"iat":   now.Unix(),
+			"nbf":   now.Unix(),
+			"exp":   now.Add(24 * time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(signed))
+	})
+
+	log.Printf("JWKS stub serving issuer %s (aud %s) on port %s", issuer, audience, port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
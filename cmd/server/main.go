@@ -1,11 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // agency timezones must resolve even without system zoneinfo
 
+	"github.com/agency-finance-reality/server/internal/auth"
 	"github.com/agency-finance-reality/server/internal/db"
 	internalHttp "github.com/agency-finance-reality/server/internal/http"
+	"github.com/agency-finance-reality/server/internal/jobs"
+	"github.com/agency-finance-reality/server/internal/notify"
 )
 
 func main() {
@@ -19,6 +26,11 @@ func main() {
 		log.Fatal("DB_URL environment variable is required")
 	}
 
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
+
 	conn, err := db.Connect(dbURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to DB: %v", err)
@@ -29,10 +41,99 @@ func main() {
 	}
 	defer conn.Close()
 
-	router := internalHttp.NewRouter(conn)
+	scheduler := jobs.NewScheduler()
+	webhook := notify.NewWebhook(10 * time.Second)
+	router := internalHttp.NewRouter(conn, authenticator, scheduler, newAlertChannels(webhook), webhook)
+	go scheduler.Run(make(chan struct{}))
 
 	log.Printf("Server starting on port %s", port)
 	if err := router.Run(":" + port); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// newAuthenticator builds the providers listed in AUTH_PROVIDER (comma
+// separated, tried in order; defaults to "cloudflare").
+func newAuthenticator() (auth.Authenticator, error) {
+	refresh := time.Hour
+	if v := os.Getenv("AUTH_JWKS_REFRESH"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_JWKS_REFRESH: %v", err)
+		}
+		refresh = d
+	}
+
+	providers := os.Getenv("AUTH_PROVIDER")
+	if providers == "" {
+		providers = "cloudflare"
+	}
+
+	var chain auth.Chain
+	for _, name := range strings.Split(providers, ",") {
+		switch strings.TrimSpace(name) {
+		case "cloudflare":
+			teamDomain := os.Getenv("CF_ACCESS_TEAM_DOMAIN")
+			audience := os.Getenv("CF_ACCESS_AUD")
+			if teamDomain == "" || audience == "" {
+				return nil, fmt.Errorf("CF_ACCESS_TEAM_DOMAIN and CF_ACCESS_AUD are required for the cloudflare provider")
+			}
+			v := auth.NewCloudflareVerifier(teamDomain, audience, os.Getenv("CF_ACCESS_CERTS_URL"), refresh)
+			if err := v.Keys().Refresh(); err != nil {
+				log.Printf("Initial Cloudflare JWKS fetch failed, will retry on demand: %v", err)
+			}
+			go v.Keys().Run(make(chan struct{}))
+			chain = append(chain, v)
+		case "oidc":
+			issuer := os.Getenv("OIDC_ISSUER")
+			audience := os.Getenv("OIDC_AUDIENCE")
+			if issuer == "" || audience == "" {
+				return nil, fmt.Errorf("OIDC_ISSUER and OIDC_AUDIENCE are required for the oidc provider")
+			}
+			a, err := auth.NewOIDCAuthenticator(issuer, audience, refresh)
+			if err != nil {
+				return nil, err
+			}
+			go a.Keys().Run(make(chan struct{}))
+			chain = append(chain, a)
+		case "dev":
+			log.Printf("WARNING: dev authentication is enabled; requests are NOT verified")
+			chain = append(chain, auth.NewDevAuthenticator(os.Getenv("AUTH_DEV_EMAIL")))
+		default:
+			return nil, fmt.Errorf("unknown auth provider %q", name)
+		}
+	}
+	return chain, nil
+}
+
+// newAlertChannels configures alert delivery. Webhooks are always available;
+// email needs SMTP_HOST and SMTP_FROM, with SMTP_PORT (default 587) and
+// optional SMTP_USERNAME and SMTP_PASSWORD.
+func newAlertChannels(webhook *notify.Webhook) map[string]notify.Channel {
+	channels := map[string]notify.Channel{
+		"webhook": webhook,
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Printf("SMTP_HOST not set; email alerts are disabled")
+		return channels
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		log.Printf("SMTP_FROM not set; email alerts are disabled")
+		return channels
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	channels["email"] = &notify.SMTP{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+	return channels
+}
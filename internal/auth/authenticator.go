@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries
+// nothing it understands, letting a Chain fall through to the next provider.
+var ErrNoCredentials = errors.New("no credentials presented")
+
+// Identity is the authenticated caller. Subject is always a UUID so it can be
+// used directly as the founder ID. Scope and APITokenID are only set for
+// requests authenticated with a personal API token.
+type Identity struct {
+	Subject    string
+	Email      string
+	Scope      string
+	APITokenID string
+}
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// Chain tries each authenticator in order. The first one that recognises the
+// request's credentials decides the outcome.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*Identity, error) {
+	for _, a := range c {
+		id, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return id, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// subjectUUID maps an external subject to a stable founder UUID, namespaced
+// by its issuer so two providers can't collide even when a subject is itself
+// a UUID.
+func subjectUUID(issuer string, sub string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(issuer+"#"+sub)).String()
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
@@ -2,27 +2,78 @@ package auth
 
 import (
 	"errors"
+	"net/http"
+	"strings"
+	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-// ValidateToken parses and validates the trusted Cloudflare token.
-// Trusted = we trust the header presence because we are behind Access.
-// But we still parse structure.
-func ValidateToken(tokenString string) (sub string, email string, err error) {
+// CloudflareVerifier validates Cloudflare Access application tokens against
+// the team's published signing keys.
+type CloudflareVerifier struct {
+	keys     *KeySet
+	audience string
+	issuer   string
+}
+
+// NewCloudflareVerifier builds a verifier for the given team domain, which is
+// also the expected issuer (https:// is assumed when no scheme is given). If
+// certsURL is empty the standard Access certs endpoint for the team is used.
+func NewCloudflareVerifier(teamDomain string, audience string, certsURL string, refresh time.Duration) *CloudflareVerifier {
+	issuer := strings.TrimSuffix(teamDomain, "/")
+	if !strings.Contains(issuer, "://") {
+		issuer = "https://" + issuer
+	}
+	if certsURL == "" {
+		certsURL = issuer + "/cdn-cgi/access/certs"
+	}
+	return &CloudflareVerifier{
+		keys:     NewKeySet(certsURL, refresh),
+		audience: audience,
+		issuer:   issuer,
+	}
+}
+
+// Keys exposes the underlying key set so callers can run periodic refreshes.
+func (v *CloudflareVerifier) Keys() *KeySet {
+	return v.keys
+}
+
+// Authenticate reads the CF-Access-Jwt-Assertion header set by Access.
+func (v *CloudflareVerifier) Authenticate(r *http.Request) (*Identity, error) {
+	tokenString := r.Header.Get("CF-Access-Jwt-Assertion")
 	if tokenString == "" {
-		return "", "", errors.New("missing token")
+		return nil, ErrNoCredentials
 	}
 
-	// Parse without signature verification (trusted upstream)
-	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	sub, email, err := v.ValidateToken(tokenString)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	return &Identity{Subject: cloudflareSubject(v.issuer, sub), Email: email}, nil
+}
+
+// cloudflareSubject keeps the founder IDs of accounts created when Cloudflare
+// Access was the only provider, which used its UUID subjects unchanged. No
+// other issuer's subjects pass through, so they can't claim those IDs.
+func cloudflareSubject(issuer string, sub string) string {
+	if id, err := uuid.Parse(sub); err == nil {
+		return id.String()
+	}
+	return subjectUUID(issuer, sub)
+}
+
+// ValidateToken checks the token signature, audience, issuer and validity
+// window, and returns the subject and email claims.
+func (v *CloudflareVerifier) ValidateToken(tokenString string) (sub string, email string, err error) {
+	if tokenString == "" {
+		return "", "", errors.New("missing token")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", "", errors.New("invalid claims")
+	claims, err := v.keys.Verify(tokenString, v.audience, v.issuer)
+	if err != nil {
+		return "", "", err
 	}
 
 	sub, _ = claims["sub"].(string)
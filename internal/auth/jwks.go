@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minForcedRefresh limits how often an unknown kid can trigger a refetch,
+// so garbage tokens can't be used to hammer the certs endpoint.
+const minForcedRefresh = 30 * time.Second
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// KeySet is a cached view of a remote JWKS document. Keys are refetched once
+// the refresh interval has elapsed, or early when a token references a kid we
+// have not seen (key rotation).
+type KeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func NewKeySet(url string, refresh time.Duration) *KeySet {
+	return &KeySet{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		keys:    make(map[string]crypto.PublicKey),
+	}
+}
+
+// Key returns the public key for kid, refreshing the set when it is stale or
+// the kid is unknown.
+func (ks *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.fetchedAt) > ks.refresh
+	canForce := time.Since(ks.lastAttempt) > minForcedRefresh
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if stale || canForce {
+		if err := ks.Refresh(); err != nil && !ok {
+			return nil, err
+		}
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok = ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// Refresh refetches the key set. On failure the previously cached keys are kept.
+func (ks *KeySet) Refresh() error {
+	ks.mu.Lock()
+	ks.lastAttempt = time.Now()
+	ks.mu.Unlock()
+
+	resp, err := ks.client.Get(ks.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var set jsonWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode jwks: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kid == "" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return errors.New("jwks contains no usable signing keys")
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the key set on its interval until stop is closed.
+func (ks *KeySet) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(ks.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ks.Refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Verify checks the token's signature against the set and validates its
+// audience, issuer, expiry and not-before claims.
+func (ks *KeySet) Verify(tokenString string, audience string, issuer string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, ks.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithAudience(audience),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+	return ks.Key(kid)
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,352 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testAudience = "test-aud"
+
+// stubIssuer serves a JWKS the way the Cloudflare Access certs endpoint and
+// cmd/jwks_stub do, with keys that can be rotated mid-test.
+type stubIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	active  []string
+	fetches int
+}
+
+func newStubIssuer(t *testing.T, kids ...string) *stubIssuer {
+	s := &stubIssuer{t: t, keys: make(map[string]*rsa.PrivateKey)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveCerts))
+	t.Cleanup(s.server.Close)
+	s.rotate(kids...)
+	return s
+}
+
+// rotate replaces the published keys with kids, generating any it hasn't
+// seen. Retired keys can still sign tokens.
+func (s *stubIssuer) rotate(kids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, kid := range kids {
+		if _, ok := s.keys[kid]; ok {
+			continue
+		}
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		s.keys[kid] = key
+	}
+	s.active = kids
+}
+
+func (s *stubIssuer) serveCerts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+
+	var set jsonWebKeySet
+	for _, kid := range s.active {
+		key := s.keys[kid]
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kid: kid,
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
+// serveDiscovery adds an OpenID Connect discovery document pointing at the
+// certs endpoint.
+func (s *stubIssuer) serveDiscovery() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{Issuer: s.server.URL, JWKSURI: s.certsURL()})
+	})
+	mux.HandleFunc("/cdn-cgi/access/certs", s.serveCerts)
+	s.server.Config.Handler = mux
+}
+
+func (s *stubIssuer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func (s *stubIssuer) certsURL() string {
+	return s.server.URL + "/cdn-cgi/access/certs"
+}
+
+// token signs claims with kid's key, filling in a valid issuer, audience and
+// validity window for any that aren't given.
+func (s *stubIssuer) token(kid string, claims jwt.MapClaims) string {
+	s.mu.Lock()
+	key := s.keys[kid]
+	s.mu.Unlock()
+	if key == nil {
+		var err error
+		if key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	defaults := jwt.MapClaims{
+		"sub":   "user-1",
+		"email": "founder@example.com",
+		"iss":   s.server.URL,
+		"aud":   []string{testAudience},
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	for k, v := range defaults {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return signed
+}
+
+// allowForcedRefresh lets the next unknown kid refetch the set straight away.
+func allowForcedRefresh(ks *KeySet) {
+	ks.mu.Lock()
+	ks.lastAttempt = time.Now().Add(-2 * minForcedRefresh)
+	ks.mu.Unlock()
+}
+
+func cloudflareRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	r.Header.Set("CF-Access-Jwt-Assertion", token)
+	return r
+}
+
+func TestCloudflareVerifierAcceptsValidToken(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	v := NewCloudflareVerifier(issuer.server.URL, testAudience, issuer.certsURL(), time.Hour)
+
+	id, err := v.Authenticate(cloudflareRequest(issuer.token("key-1", jwt.MapClaims{})))
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if id.Email != "founder@example.com" {
+		t.Errorf("email = %q, want founder@example.com", id.Email)
+	}
+	if want := subjectUUID(issuer.server.URL, "user-1"); id.Subject != want {
+		t.Errorf("subject = %q, want %q", id.Subject, want)
+	}
+}
+
+func TestCloudflareVerifierRejectsInvalidTokens(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "expired",
+			token: issuer.token("key-1", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}),
+		},
+		{
+			name:  "no expiry",
+			token: issuer.token("key-1", jwt.MapClaims{"exp": nil}),
+		},
+		{
+			name:  "not yet valid",
+			token: issuer.token("key-1", jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()}),
+		},
+		{
+			name:  "wrong audience",
+			token: issuer.token("key-1", jwt.MapClaims{"aud": []string{"another-app"}}),
+		},
+		{
+			name:  "wrong issuer",
+			token: issuer.token("key-1", jwt.MapClaims{"iss": "https://evil.example.com"}),
+		},
+		{
+			name:  "unknown kid",
+			token: issuer.token("key-unpublished", jwt.MapClaims{}),
+		},
+		{
+			name:  "missing email",
+			token: issuer.token("key-1", jwt.MapClaims{"email": ""}),
+		},
+		{
+			name:  "garbage",
+			token: "not-a-jwt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewCloudflareVerifier(issuer.server.URL, testAudience, issuer.certsURL(), time.Hour)
+			if id, err := v.Authenticate(cloudflareRequest(tt.token)); err == nil {
+				t.Fatalf("Authenticate accepted the token as %+v", id)
+			}
+		})
+	}
+}
+
+func TestCloudflareVerifierWithoutHeader(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	v := NewCloudflareVerifier(issuer.server.URL, testAudience, issuer.certsURL(), time.Hour)
+
+	_, err := v.Authenticate(httptest.NewRequest(http.MethodGet, "/api/me", nil))
+	if err != ErrNoCredentials {
+		t.Fatalf("err = %v, want ErrNoCredentials", err)
+	}
+	if n := issuer.fetchCount(); n != 0 {
+		t.Errorf("fetched the key set %d times for a request without a token", n)
+	}
+}
+
+func TestKeySetFollowsKeyRotation(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	ks := NewKeySet(issuer.certsURL(), time.Hour)
+
+	if _, err := ks.Verify(issuer.token("key-1", jwt.MapClaims{}), testAudience, issuer.server.URL); err != nil {
+		t.Fatalf("verify with key-1: %v", err)
+	}
+
+	issuer.rotate("key-2")
+	allowForcedRefresh(ks)
+
+	if _, err := ks.Verify(issuer.token("key-2", jwt.MapClaims{}), testAudience, issuer.server.URL); err != nil {
+		t.Fatalf("verify with rotated key-2: %v", err)
+	}
+	if n := issuer.fetchCount(); n != 2 {
+		t.Errorf("fetches = %d, want 2", n)
+	}
+	if _, err := ks.Verify(issuer.token("key-1", jwt.MapClaims{}), testAudience, issuer.server.URL); err == nil {
+		t.Error("token signed with the retired key-1 was accepted")
+	}
+}
+
+func TestKeySetLimitsForcedRefreshes(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	ks := NewKeySet(issuer.certsURL(), time.Hour)
+
+	for i := 0; i < 5; i++ {
+		if _, err := ks.Key("key-unknown"); err == nil {
+			t.Fatal("unknown kid resolved to a key")
+		}
+	}
+	if n := issuer.fetchCount(); n != 1 {
+		t.Errorf("fetches = %d, want 1 within the forced refresh interval", n)
+	}
+
+	// A kid published after the first fetch waits for the interval too
+	issuer.rotate("key-1", "key-2")
+	if _, err := ks.Key("key-2"); err == nil {
+		t.Error("key-2 resolved before a refresh was allowed")
+	}
+	allowForcedRefresh(ks)
+	if _, err := ks.Key("key-2"); err != nil {
+		t.Errorf("key-2 after refresh: %v", err)
+	}
+}
+
+func TestKeySetKeepsKeysWhenRefreshFails(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	ks := NewKeySet(issuer.certsURL(), time.Hour)
+	if _, err := ks.Key("key-1"); err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+
+	issuer.server.Close()
+	if err := ks.Refresh(); err == nil {
+		t.Fatal("Refresh succeeded against a closed server")
+	}
+	if _, err := ks.Key("key-1"); err != nil {
+		t.Errorf("cached key-1 lost after a failed refresh: %v", err)
+	}
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	issuer := newStubIssuer(t, "key-1")
+	issuer.serveDiscovery()
+
+	a, err := NewOIDCAuthenticator(issuer.server.URL, testAudience, time.Hour)
+	if err != nil {
+		t.Fatalf("NewOIDCAuthenticator: %v", err)
+	}
+
+	bearer := func(token string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		return r
+	}
+
+	id, err := a.Authenticate(bearer(issuer.token("key-1", jwt.MapClaims{"email_verified": true})))
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if want := subjectUUID(issuer.server.URL, "user-1"); id.Subject != want {
+		t.Errorf("subject = %q, want %q", id.Subject, want)
+	}
+
+	if _, err := a.Authenticate(bearer(issuer.token("key-1", jwt.MapClaims{"email_verified": false}))); err == nil {
+		t.Error("token with an unverified email was accepted")
+	}
+	if _, err := a.Authenticate(bearer(issuer.token("key-1", jwt.MapClaims{"aud": "another-app"}))); err == nil {
+		t.Error("token for another audience was accepted")
+	}
+}
+
+func TestUUIDSubjectsOnlyPassThroughForCloudflare(t *testing.T) {
+	const sub = "7d444840-9dc0-11d1-b245-5ffdce74fad2"
+	issuer := newStubIssuer(t, "key-1")
+	issuer.serveDiscovery()
+	token := issuer.token("key-1", jwt.MapClaims{"sub": sub})
+
+	cf := NewCloudflareVerifier(issuer.server.URL, testAudience, issuer.certsURL(), time.Hour)
+	cfID, err := cf.Authenticate(cloudflareRequest(token))
+	if err != nil {
+		t.Fatalf("Cloudflare: %v", err)
+	}
+	if cfID.Subject != sub {
+		t.Errorf("Cloudflare subject = %q, want the legacy %q", cfID.Subject, sub)
+	}
+
+	oidc, err := NewOIDCAuthenticator(issuer.server.URL, testAudience, time.Hour)
+	if err != nil {
+		t.Fatalf("NewOIDCAuthenticator: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	oidcID, err := oidc.Authenticate(r)
+	if err != nil {
+		t.Fatalf("OIDC: %v", err)
+	}
+	if oidcID.Subject == sub {
+		t.Error("OIDC subject passed through unnamespaced and collides with the Cloudflare founder")
+	}
+	if want := subjectUUID(issuer.server.URL, sub); oidcID.Subject != want {
+		t.Errorf("OIDC subject = %q, want %q", oidcID.Subject, want)
+	}
+}
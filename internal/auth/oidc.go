@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OIDCAuthenticator accepts `Authorization: Bearer <id token>` issued by any
+// OpenID Connect provider. Signing keys are located via issuer discovery.
+type OIDCAuthenticator struct {
+	keys     *KeySet
+	audience string
+	issuer   string
+}
+
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// NewOIDCAuthenticator fetches <issuer>/.well-known/openid-configuration and
+// prepares a key set for the advertised jwks_uri.
+func NewOIDCAuthenticator(issuer string, audience string, refresh time.Duration) (*OIDCAuthenticator, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery failed: status %d", resp.StatusCode)
+	}
+
+	var doc oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %v", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: got %q", doc.Issuer)
+	}
+	if doc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document has no jwks_uri")
+	}
+
+	return &OIDCAuthenticator{
+		keys:     NewKeySet(doc.JWKSURI, refresh),
+		audience: audience,
+		issuer:   doc.Issuer,
+	}, nil
+}
+
+func (a *OIDCAuthenticator) Keys() *KeySet {
+	return a.keys
+}
+
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	tokenString := bearerToken(r)
+	if tokenString == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims, err := a.keys.Verify(tokenString, a.audience, a.issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	if sub == "" || email == "" {
+		return nil, errors.New("missing subject or email in token")
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, errors.New("email not verified")
+	}
+
+	return &Identity{Subject: subjectUUID(a.issuer, sub), Email: email}, nil
+}
@@ -0,0 +1,25 @@
+package clock
+
+import "time"
+
+// Clock is the source of "now" for services, so date logic can be driven by
+// a fixed time instead of the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real reads the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed always returns T.
+type Fixed struct {
+	T time.Time
+}
+
+func (f Fixed) Now() time.Time {
+	return f.T
+}
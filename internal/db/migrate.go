@@ -11,8 +11,17 @@ import (
 )
 
 func RunMigrations(db *sql.DB) error {
-	// Simple migration runner: reads all .sql files in migrations/ and executes them.
-	// In a real app we'd track versions in a table. For this "skeleton", re-running valid idempotent SQL (CREATE IF NOT EXISTS) is fine.
+	// Simple migration runner: reads all .sql files in migrations/ and executes each one once,
+	// recording applied file names in schema_migrations. Migrations written before tracking existed
+	// are idempotent (CREATE IF NOT EXISTS), so databases that predate the table replay them safely.
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name TEXT PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
 
 	// Assuming migrations dir is passed or we find it relative to cwd
 	files, err := ioutil.ReadDir("migrations")
@@ -26,17 +35,43 @@ func RunMigrations(db *sql.DB) error {
 	})
 
 	for _, f := range files {
-		if filepath.Ext(f.Name()) == ".sql" {
-			log.Printf("Applying migration: %s", f.Name())
-			content, err := os.ReadFile(filepath.Join("migrations", f.Name()))
-			if err != nil {
-				return fmt.Errorf("failed to read migration %s: %v", f.Name(), err)
-			}
-
-			if _, err := db.Exec(string(content)); err != nil {
-				return fmt.Errorf("failed to execute migration %s: %v", f.Name(), err)
-			}
+		if filepath.Ext(f.Name()) != ".sql" {
+			continue
+		}
+
+		var applied bool
+		if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE name = $1)", f.Name()).Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %v", f.Name(), err)
+		}
+		if applied {
+			continue
+		}
+
+		log.Printf("Applying migration: %s", f.Name())
+		content, err := os.ReadFile(filepath.Join("migrations", f.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %v", f.Name(), err)
+		}
+
+		if err := applyMigration(db, f.Name(), string(content)); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %v", f.Name(), err)
 		}
 	}
 	return nil
 }
+
+func applyMigration(db *sql.DB, name string, content string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(content); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (name) VALUES ($1)", name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
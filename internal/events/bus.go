@@ -0,0 +1,79 @@
+// Package events carries notices of changes to agency data from the services
+// that make them to whatever reacts, such as alert rules.
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types published by the services.
+const (
+	RevenueCreated       = "revenue.created"
+	RevenueUpdated       = "revenue.updated"
+	RevenueDeleted       = "revenue.deleted"
+	CostCreated          = "cost.created"
+	CostUpdated          = "cost.updated"
+	CostDeleted          = "cost.deleted"
+	CashSnapshotRecorded = "cash_snapshot.recorded"
+	BankSnapshotRecorded = "bank_snapshot.recorded"
+	RecurringCostChanged = "recurring_cost.changed"
+	ClientCreated        = "client.created"
+	RetainerCreated      = "retainer.created"
+	RetainerUpdated      = "retainer.updated"
+	TimeEntryCreated     = "time_entry.created"
+	ScoreStatusChanged   = "score.status_changed"
+)
+
+// Event is a change that has been committed. Data is the changed record's
+// view, or nil when there's nothing more to say than the type.
+type Event struct {
+	Type       string
+	AgencyID   string
+	OccurredAt time.Time
+	Data       interface{}
+}
+
+// Handler reacts to an event. Errors are logged; the write that raised the
+// event has already succeeded.
+type Handler func(Event) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber{name: name, handler: handler})
+}
+
+// Publish hands e to every subscriber on its own goroutine so the request
+// that caused it isn't held up. A nil Bus drops events.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	subscribers := append([]subscriber(nil), b.subscribers...)
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		go func(s subscriber) {
+			if err := s.handler(e); err != nil {
+				log.Printf("Event %s for agency %s: %s failed: %v", e.Type, e.AgencyID, s.name, err)
+			}
+		}(s)
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AgencyHeader selects which of the caller's agencies a request acts on.
+const AgencyHeader = "X-Agency-ID"
+
+// currentAgency resolves the caller's agency from the X-Agency-ID header (or
+// their only agency), writing the error response when it can't.
+func currentAgency(c *gin.Context, agencyService services.AgencyService) (*models.AgencyView, bool) {
+	userID := c.MustGet("user_id").(string)
+	agency, err := agencyService.ResolveAgency(userID, c.GetHeader(AgencyHeader))
+	if errors.Is(err, services.ErrAgencySelectionRequired) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if errors.Is(err, services.ErrAgencyNotFound) {
+		SendError(c, http.StatusNotFound, "Agency not found")
+		return nil, false
+	}
+	if err != nil {
+		SendInternalError(c)
+		return nil, false
+	}
+	return agency, true
+}
+
+// agencyWithRole is currentAgency plus a role check, responding 403 when the
+// caller's membership role is not one of roles.
+func agencyWithRole(c *gin.Context, agencyService services.AgencyService, roles ...string) (*models.AgencyView, bool) {
+	agency, ok := currentAgency(c, agencyService)
+	if !ok {
+		return nil, false
+	}
+	for _, role := range roles {
+		if agency.Role == role {
+			return agency, true
+		}
+	}
+	SendError(c, http.StatusForbidden, "Insufficient role for this action")
+	return nil, false
+}
+
+// writableAgency is the agency for ledger writes (owner and finance roles).
+func writableAgency(c *gin.Context, agencyService services.AgencyService) (*models.AgencyView, bool) {
+	return agencyWithRole(c, agencyService, services.RoleOwner, services.RoleFinance)
+}
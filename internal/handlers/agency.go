@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/agency-finance-reality/server/internal/services"
@@ -16,13 +17,21 @@ func NewAgencyHandler(agencyService services.AgencyService) *AgencyHandler {
 }
 
 func (h *AgencyHandler) GetAgency(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, agency)
+}
+
+func (h *AgencyHandler) ListAgencies(c *gin.Context) {
 	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
+	agencies, err := h.agencyService.ListAgencies(userID)
 	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+		SendInternalError(c)
 		return
 	}
-	c.JSON(http.StatusOK, agency)
+	c.JSON(http.StatusOK, agencies)
 }
 
 type CreateAgencyRequest struct {
@@ -39,11 +48,76 @@ func (h *AgencyHandler) CreateAgency(c *gin.Context) {
 		return
 	}
 
-	err := h.agencyService.CreateAgency(userID, req.Name, req.BaseCurrency, req.StartingCash)
+	agency, err := h.agencyService.CreateAgency(userID, req.Name, req.BaseCurrency, req.StartingCash)
+	if errors.Is(err, services.ErrAgencyExists) {
+		SendError(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, agency)
+}
+
+func (h *AgencyHandler) GetMembers(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	members, err := h.agencyService.ListMembers(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner finance viewer"`
+}
+
+func (h *AgencyHandler) InviteMember(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	invitation, err := h.agencyService.InviteMember(agency.ID, userID, req.Email, req.Role)
+	if errors.Is(err, services.ErrAlreadyMember) || errors.Is(err, services.ErrAlreadyInvited) {
+		SendError(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *AgencyHandler) GetInvitations(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.agencyService.ListInvitations(agency.ID)
 	if err != nil {
 		SendInternalError(c)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusOK, invitations)
 }
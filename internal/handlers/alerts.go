@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	agencyService services.AgencyService
+	alertService  services.AlertService
+}
+
+func NewAlertHandler(agencyService services.AgencyService, alertService services.AlertService) *AlertHandler {
+	return &AlertHandler{
+		agencyService: agencyService,
+		alertService:  alertService,
+	}
+}
+
+// AlertRuleRequest sets a whole rule. Leave out both min and max to use the
+// metric's default bounds.
+type AlertRuleRequest struct {
+	Metric          string   `json:"metric" binding:"required"`
+	Min             *float64 `json:"min"`
+	Max             *float64 `json:"max"`
+	Channel         string   `json:"channel" binding:"required,oneof=email webhook"`
+	Target          string   `json:"target" binding:"required"`
+	CooldownMinutes *int     `json:"cooldown_minutes" binding:"omitempty,min=0"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+func (r AlertRuleRequest) input() services.AlertRuleInput {
+	return services.AlertRuleInput{
+		Metric:          r.Metric,
+		Min:             r.Min,
+		Max:             r.Max,
+		Channel:         r.Channel,
+		Target:          r.Target,
+		CooldownMinutes: r.CooldownMinutes,
+		Enabled:         r.Enabled,
+	}
+}
+
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	rules, err := h.alertService.ListRules(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(agency.ID, req.input())
+	if !handleAlertError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(agency.ID, c.Param("id"), req.input())
+	if !handleAlertError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	err := h.alertService.DeleteRule(agency.ID, c.Param("id"))
+	if !handleAlertError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAlerts returns alert history, newest first, up to ?limit= alerts.
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid limit: expected a number")
+			return
+		}
+		limit = n
+	}
+
+	alerts, err := h.alertService.ListAlerts(agency.ID, limit)
+	if !handleAlertError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// handleAlertError writes the response for a failed alert operation and
+// reports whether the caller should continue.
+func handleAlertError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrAlertRuleNotFound):
+		SendError(c, http.StatusNotFound, "Alert rule not found")
+	case errors.Is(err, services.ErrInvalidAlertRule), errors.Is(err, services.ErrInvalidDateRange):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
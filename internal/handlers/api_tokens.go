@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type APITokenHandler struct {
+	tokenService services.APITokenService
+}
+
+func NewAPITokenHandler(tokenService services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{tokenService: tokenService}
+}
+
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scope     string     `json:"scope" binding:"required,oneof=read read_write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	if !requireInteractiveAuth(c) {
+		return
+	}
+	userID := c.MustGet("user_id").(string)
+
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	token, err := h.tokenService.CreateToken(userID, req.Name, req.Scope, req.ExpiresAt)
+	if errors.Is(err, services.ErrInvalidToken) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	if !requireInteractiveAuth(c) {
+		return
+	}
+	userID := c.MustGet("user_id").(string)
+
+	tokens, err := h.tokenService.ListTokens(userID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	if !requireInteractiveAuth(c) {
+		return
+	}
+	userID := c.MustGet("user_id").(string)
+
+	err := h.tokenService.RevokeToken(userID, c.Param("id"))
+	if errors.Is(err, services.ErrTokenNotFound) {
+		SendError(c, http.StatusNotFound, "Token not found")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireInteractiveAuth stops API tokens from minting or revoking tokens.
+func requireInteractiveAuth(c *gin.Context) bool {
+	if c.GetString("api_token_id") != "" {
+		SendError(c, http.StatusForbidden, "API tokens cannot manage API tokens")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type BankAccountHandler struct {
+	agencyService      services.AgencyService
+	bankAccountService services.BankAccountService
+}
+
+func NewBankAccountHandler(agencyService services.AgencyService, bankAccountService services.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{
+		agencyService:      agencyService,
+		bankAccountService: bankAccountService,
+	}
+}
+
+func (h *BankAccountHandler) ListAccounts(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.bankAccountService.ListAccounts(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+type CreateBankAccountRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Currency   string `json:"currency" binding:"required,len=3"`
+	Restricted bool   `json:"restricted"`
+}
+
+func (h *BankAccountHandler) CreateAccount(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req CreateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	account, err := h.bankAccountService.CreateAccount(agency.ID, req.Name, req.Currency, req.Restricted)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+type UpdateBankAccountRequest struct {
+	Name       *string `json:"name" binding:"omitempty,min=1"`
+	Restricted *bool   `json:"restricted"`
+}
+
+func (h *BankAccountHandler) UpdateAccount(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	account, err := h.bankAccountService.UpdateAccount(agency.ID, c.Param("id"), services.BankAccountUpdate{
+		Name:       req.Name,
+		Restricted: req.Restricted,
+	})
+	if !handleAccountError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) ArchiveAccount(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	err := h.bankAccountService.ArchiveAccount(agency.ID, userID, c.Param("id"))
+	if !handleAccountError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+type RecordAccountSnapshotRequest struct {
+	Balance      *float64 `json:"balance" binding:"required"`
+	ExchangeRate *float64 `json:"exchange_rate" binding:"omitempty,gt=0"`
+	Date         string   `json:"date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (h *BankAccountHandler) RecordSnapshot(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req RecordAccountSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	snap, err := h.bankAccountService.RecordAccountSnapshot(agency.ID, userID, c.Param("id"), req.Date, *req.Balance, req.ExchangeRate)
+	if !handleAccountError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, snap)
+}
+
+// handleAccountError writes the response for a failed account operation and
+// reports whether the caller should continue.
+func handleAccountError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrAccountNotFound):
+		SendError(c, http.StatusNotFound, "Bank account not found")
+	case errors.Is(err, services.ErrInvalidEntryDate), errors.Is(err, services.ErrInvalidExchangeRate):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
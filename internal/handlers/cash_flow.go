@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CashFlowHandler struct {
+	agencyService   services.AgencyService
+	cashFlowService services.CashFlowService
+}
+
+func NewCashFlowHandler(agencyService services.AgencyService, cashFlowService services.CashFlowService) *CashFlowHandler {
+	return &CashFlowHandler{
+		agencyService:   agencyService,
+		cashFlowService: cashFlowService,
+	}
+}
+
+func (h *CashFlowHandler) GetThirteenWeekReport(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	report, err := h.cashFlowService.GetThirteenWeekReport(agency.ID)
+	if errors.Is(err, services.ErrNoCashSnapshot) {
+		SendError(c, http.StatusNotFound, "No cash snapshot recorded yet")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+type RecordCashFlowActualRequest struct {
+	Inflows        *float64 `json:"inflows" binding:"required,min=0"`
+	Outflows       *float64 `json:"outflows" binding:"required,min=0"`
+	ClosingBalance *float64 `json:"closing_balance" binding:"required"`
+}
+
+func (h *CashFlowHandler) RecordWeekActual(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req RecordCashFlowActualRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	err := h.cashFlowService.RecordWeekActual(agency.ID, userID, c.Param("week_start"), services.CashFlowActual{
+		Inflows:        *req.Inflows,
+		Outflows:       *req.Outflows,
+		ClosingBalance: *req.ClosingBalance,
+	})
+	if errors.Is(err, services.ErrInvalidCashFlowWeek) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/agency-finance-reality/server/internal/services"
 	"github.com/gin-gonic/gin"
@@ -20,14 +22,12 @@ func NewCashSnapshotHandler(agencyService services.AgencyService, financeService
 }
 
 func (h *CashSnapshotHandler) GetTodaysCash(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
-	snap, err := h.financeService.GetDailySnapshot(agency.ID)
+	snap, err := h.financeService.GetDailySnapshot(agency.ID, "")
 	if err != nil {
 		SendInternalError(c)
 		return
@@ -40,15 +40,48 @@ func (h *CashSnapshotHandler) GetTodaysCash(c *gin.Context) {
 	c.JSON(http.StatusOK, snap)
 }
 
+func (h *CashSnapshotHandler) GetCashOnDate(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	date := c.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid date: expected YYYY-MM-DD")
+		return
+	}
+
+	snap, err := h.financeService.GetDailySnapshot(agency.ID, date)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+	if snap == nil {
+		SendError(c, http.StatusNotFound, "No snapshot on "+date)
+		return
+	}
+
+	c.JSON(http.StatusOK, snap)
+}
+
 type RecordCashRequest struct {
-	CashBalance float64 `json:"cash_balance" binding:"required"`
+	CashBalance *float64 `json:"cash_balance" binding:"required"`
 }
 
+// RecordDailyCash records today's balance, overwriting an earlier entry for today.
 func (h *CashSnapshotHandler) RecordDailyCash(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	h.recordCash(c, "", http.StatusCreated)
+}
+
+// PutCashOnDate sets or corrects the balance for the date in the path.
+func (h *CashSnapshotHandler) PutCashOnDate(c *gin.Context) {
+	h.recordCash(c, c.Param("date"), http.StatusOK)
+}
+
+func (h *CashSnapshotHandler) recordCash(c *gin.Context, date string, status int) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -58,11 +91,39 @@ func (h *CashSnapshotHandler) RecordDailyCash(c *gin.Context) {
 		return
 	}
 
-	err = h.financeService.RecordCashSnapshot(agency.ID, req.CashBalance)
+	userID := c.MustGet("user_id").(string)
+	snap, err := h.financeService.RecordCashSnapshot(agency.ID, userID, date, *req.CashBalance)
+	if errors.Is(err, services.ErrInvalidEntryDate) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrCashTrackedByAccounts) {
+		SendError(c, http.StatusConflict, "Cash is recorded per bank account; post a snapshot to /bank-accounts/:id/snapshots instead")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(status, snap)
+}
+
+func (h *CashSnapshotHandler) GetCashHistory(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	history, err := h.financeService.GetCashHistory(agency.ID, c.Query("from"), c.Query("to"), c.Query("interval"))
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		SendInternalError(c)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusOK, history)
 }
@@ -24,10 +24,8 @@ type CreateClientRequest struct {
 }
 
 func (h *ClientHandler) CreateClient(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -47,10 +45,8 @@ func (h *ClientHandler) CreateClient(c *gin.Context) {
 }
 
 func (h *ClientHandler) GetClients(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/agency-finance-reality/server/internal/services"
@@ -22,13 +23,12 @@ func NewDailyFinanceHandler(agencyService services.AgencyService, financeService
 type AddRevenueRequest struct {
 	Amount float64 `json:"amount" binding:"required,gt=0"`
 	Source string  `json:"source" binding:"required"`
+	Date   string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
 }
 
 func (h *DailyFinanceHandler) AddRevenue(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -38,27 +38,30 @@ func (h *DailyFinanceHandler) AddRevenue(c *gin.Context) {
 		return
 	}
 
-	err = h.financeService.AddRevenue(agency.ID, req.Amount, req.Source)
+	id, err := h.financeService.AddRevenue(agency.ID, req.Date, req.Amount, req.Source)
+	if errors.Is(err, services.ErrInvalidEntryDate) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		SendInternalError(c)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
 
 type AddCostRequest struct {
 	Amount   float64 `json:"amount" binding:"required,gt=0"`
-	Type     string  `json:"type" binding:"required"`
+	Type     string  `json:"type" binding:"required,oneof=fixed variable"`
 	Label    string  `json:"label" binding:"required"`
 	Category string  `json:"category" binding:"required,oneof=people tools other"`
+	Date     string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
 }
 
 func (h *DailyFinanceHandler) AddCost(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -68,20 +71,22 @@ func (h *DailyFinanceHandler) AddCost(c *gin.Context) {
 		return
 	}
 
-	err = h.financeService.AddCost(agency.ID, req.Amount, req.Type, req.Label, req.Category)
+	id, err := h.financeService.AddCost(agency.ID, req.Date, req.Amount, req.Type, req.Label, req.Category)
+	if errors.Is(err, services.ErrInvalidEntryDate) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		SendInternalError(c)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
 
 func (h *DailyFinanceHandler) GetCostBreakdown(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -95,10 +100,8 @@ func (h *DailyFinanceHandler) GetCostBreakdown(c *gin.Context) {
 }
 
 func (h *DailyFinanceHandler) GetDailySummary(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -110,3 +113,149 @@ func (h *DailyFinanceHandler) GetDailySummary(c *gin.Context) {
 
 	c.JSON(http.StatusOK, summary)
 }
+
+func (h *DailyFinanceHandler) ListRevenues(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	revenues, err := h.financeService.ListRevenues(agency.ID, c.Query("from"), c.Query("to"))
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, revenues)
+}
+
+type UpdateRevenueRequest struct {
+	Amount *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Source *string  `json:"source" binding:"omitempty,min=1"`
+	Date   *string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (h *DailyFinanceHandler) UpdateRevenue(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateRevenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	revenue, err := h.financeService.UpdateRevenue(agency.ID, c.Param("id"), services.RevenueUpdate{
+		Date:   req.Date,
+		Amount: req.Amount,
+		Source: req.Source,
+	})
+	if !handleEntryError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, revenue)
+}
+
+func (h *DailyFinanceHandler) DeleteRevenue(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.financeService.DeleteRevenue(agency.ID, c.Param("id"))
+	if !handleEntryError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DailyFinanceHandler) ListCosts(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	costs, err := h.financeService.ListCosts(agency.ID, c.Query("from"), c.Query("to"))
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, costs)
+}
+
+type UpdateCostRequest struct {
+	Amount   *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Type     *string  `json:"type" binding:"omitempty,oneof=fixed variable"`
+	Label    *string  `json:"label" binding:"omitempty,min=1"`
+	Category *string  `json:"category" binding:"omitempty,oneof=people tools other"`
+	Date     *string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (h *DailyFinanceHandler) UpdateCost(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	cost, err := h.financeService.UpdateCost(agency.ID, c.Param("id"), services.CostUpdate{
+		Date:     req.Date,
+		Amount:   req.Amount,
+		Type:     req.Type,
+		Label:    req.Label,
+		Category: req.Category,
+	})
+	if !handleEntryError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+func (h *DailyFinanceHandler) DeleteCost(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.financeService.DeleteCost(agency.ID, c.Param("id"))
+	if !handleEntryError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleEntryError maps ledger entry errors to responses, returning true when
+// there was no error.
+func handleEntryError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrEntryNotFound):
+		SendError(c, http.StatusNotFound, "Entry not found")
+	case errors.Is(err, services.ErrInvalidEntryDate):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
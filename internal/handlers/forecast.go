@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ForecastHandler struct {
+	agencyService   services.AgencyService
+	forecastService services.ForecastService
+}
+
+func NewForecastHandler(agencyService services.AgencyService, forecastService services.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		agencyService:   agencyService,
+		forecastService: forecastService,
+	}
+}
+
+func (h *ForecastHandler) GetCashForecast(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	months := 12
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid months: expected a number")
+			return
+		}
+		months = n
+	}
+	excludeRestricted := c.Query("exclude_restricted") == "true"
+
+	forecast, err := h.forecastService.GetCashForecast(agency.ID, months, excludeRestricted)
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrNoCashSnapshot) {
+		SendError(c, http.StatusNotFound, "No cash snapshot recorded yet")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// GetRunwayDistribution accepts ?simulations= and ?seed=; passing back the
+// returned seed reproduces a run.
+func (h *ForecastHandler) GetRunwayDistribution(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	params := services.RunwaySimulationParams{
+		ExcludeRestricted: c.Query("exclude_restricted") == "true",
+	}
+	if v := c.Query("simulations"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid simulations: expected a number")
+			return
+		}
+		params.Simulations = n
+	}
+	if v := c.Query("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid seed: expected a number")
+			return
+		}
+		params.Seed = &seed
+	}
+
+	distribution, err := h.forecastService.GetRunwayDistribution(agency.ID, params)
+	if errors.Is(err, services.ErrInvalidSimulations) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientHistory) {
+		SendError(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrNoCashSnapshot) {
+		SendError(c, http.StatusNotFound, "No cash snapshot recorded yet")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, distribution)
+}
+
+func (h *ForecastHandler) ListPlannedItems(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	items, err := h.forecastService.ListPlannedItems(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+type CreatePlannedItemRequest struct {
+	Date      string  `json:"date" binding:"required,datetime=2006-01-02"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Direction string  `json:"direction" binding:"required,oneof=inflow outflow"`
+	Label     string  `json:"label" binding:"required"`
+}
+
+func (h *ForecastHandler) CreatePlannedItem(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req CreatePlannedItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	item, err := h.forecastService.CreatePlannedItem(agency.ID, req.Date, req.Amount, req.Direction, req.Label)
+	if errors.Is(err, services.ErrInvalidEntryDate) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *ForecastHandler) DeletePlannedItem(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.forecastService.DeletePlannedItem(agency.ID, c.Param("id"))
+	if errors.Is(err, services.ErrPlannedItemNotFound) {
+		SendError(c, http.StatusNotFound, "Planned item not found")
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceHandler struct {
+	agencyService  services.AgencyService
+	invoiceService services.InvoiceService
+}
+
+func NewInvoiceHandler(agencyService services.AgencyService, invoiceService services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		agencyService:  agencyService,
+		invoiceService: invoiceService,
+	}
+}
+
+// ListInvoices lists every invoice, or only unpaid ones with
+// ?status=outstanding.
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	invoices, err := h.invoiceService.ListInvoices(agency.ID, c.Query("status") == "outstanding")
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+type CreateInvoiceRequest struct {
+	ClientID *string `json:"client_id" binding:"omitempty,uuid"`
+	Number   string  `json:"number" binding:"required"`
+	Amount   float64 `json:"amount" binding:"required,gt=0"`
+	DueDate  string  `json:"due_date" binding:"required,datetime=2006-01-02"`
+}
+
+func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req CreateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(agency.ID, services.InvoiceInput{
+		ClientID: req.ClientID,
+		Number:   req.Number,
+		Amount:   req.Amount,
+		DueDate:  req.DueDate,
+	})
+	if !handleInvoiceError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+type UpdateInvoiceRequest struct {
+	Number  *string  `json:"number" binding:"omitempty,min=1"`
+	Amount  *float64 `json:"amount" binding:"omitempty,gt=0"`
+	DueDate *string  `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	// An empty paid_on marks the invoice outstanding again
+	PaidOn *string `json:"paid_on" binding:"omitempty,datetime=2006-01-02|len=0"`
+}
+
+func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	invoice, err := h.invoiceService.UpdateInvoice(agency.ID, c.Param("id"), services.InvoiceUpdate{
+		Number:  req.Number,
+		Amount:  req.Amount,
+		DueDate: req.DueDate,
+		PaidOn:  req.PaidOn,
+	})
+	if !handleInvoiceError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.invoiceService.DeleteInvoice(agency.ID, c.Param("id"))
+	if !handleInvoiceError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleInvoiceError writes the response for a failed invoice operation and
+// reports whether the caller should continue.
+func handleInvoiceError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvoiceNotFound):
+		SendError(c, http.StatusNotFound, "Invoice not found")
+	case errors.Is(err, services.ErrInvalidInvoice):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
@@ -20,10 +20,8 @@ func NewRealityScoreHandler(agencyService services.AgencyService, financeService
 }
 
 func (h *RealityScoreHandler) GetRealityScore(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
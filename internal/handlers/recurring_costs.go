@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringCostHandler struct {
+	agencyService        services.AgencyService
+	recurringCostService services.RecurringCostService
+}
+
+func NewRecurringCostHandler(agencyService services.AgencyService, recurringCostService services.RecurringCostService) *RecurringCostHandler {
+	return &RecurringCostHandler{
+		agencyService:        agencyService,
+		recurringCostService: recurringCostService,
+	}
+}
+
+func (h *RecurringCostHandler) ListRecurringCosts(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	costs, err := h.recurringCostService.ListRecurringCosts(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, costs)
+}
+
+type CreateRecurringCostRequest struct {
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Label     string  `json:"label" binding:"required"`
+	Category  string  `json:"category" binding:"required,oneof=people tools other"`
+	Cadence   string  `json:"cadence" binding:"required,oneof=weekly monthly annual"`
+	StartDate string  `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   *string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (h *RecurringCostHandler) CreateRecurringCost(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req CreateRecurringCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	cost, err := h.recurringCostService.CreateRecurringCost(agency.ID, services.RecurringCostInput{
+		Amount:    req.Amount,
+		Label:     req.Label,
+		Category:  req.Category,
+		Cadence:   req.Cadence,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if !handleRecurringCostError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, cost)
+}
+
+type UpdateRecurringCostRequest struct {
+	Amount   *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Label    *string  `json:"label" binding:"omitempty,min=1"`
+	Category *string  `json:"category" binding:"omitempty,oneof=people tools other"`
+	// An empty end_date makes the template open-ended again
+	EndDate *string `json:"end_date" binding:"omitempty,datetime=2006-01-02|len=0"`
+}
+
+func (h *RecurringCostHandler) UpdateRecurringCost(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateRecurringCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	cost, err := h.recurringCostService.UpdateRecurringCost(agency.ID, c.Param("id"), services.RecurringCostUpdate{
+		Amount:   req.Amount,
+		Label:    req.Label,
+		Category: req.Category,
+		EndDate:  req.EndDate,
+	})
+	if !handleRecurringCostError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+func (h *RecurringCostHandler) DeleteRecurringCost(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.recurringCostService.DeleteRecurringCost(agency.ID, c.Param("id"))
+	if !handleRecurringCostError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RecurringCostHandler) ListUpcoming(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	days := 30
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid days: expected a number")
+			return
+		}
+		days = n
+	}
+
+	upcoming, err := h.recurringCostService.ListUpcoming(agency.ID, days)
+	if !handleRecurringCostError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, upcoming)
+}
+
+// handleRecurringCostError writes the response for a failed template
+// operation and reports whether the caller should continue.
+func handleRecurringCostError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrRecurringCostNotFound):
+		SendError(c, http.StatusNotFound, "Recurring cost not found")
+	case errors.Is(err, services.ErrInvalidRecurringCost), errors.Is(err, services.ErrInvalidDateRange):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/agency-finance-reality/server/internal/services"
@@ -22,13 +23,15 @@ func NewRetainerHandler(agencyService services.AgencyService, clientService serv
 type CreateRetainerRequest struct {
 	ClientID      string  `json:"client_id" binding:"required"`
 	MonthlyAmount float64 `json:"monthly_amount" binding:"required,gt=0"`
+	// Monthly probability of losing the retainer, 0-1
+	ChurnProbability float64 `json:"churn_probability" binding:"min=0,max=1"`
+	// Day of the month the retainer is paid; defaults to the 1st
+	BillingDay int `json:"billing_day" binding:"omitempty,min=1,max=28"`
 }
 
 func (h *RetainerHandler) CreateRetainer(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -38,7 +41,7 @@ func (h *RetainerHandler) CreateRetainer(c *gin.Context) {
 		return
 	}
 
-	err = h.clientService.CreateRetainer(agency.ID, req.ClientID, req.MonthlyAmount)
+	err := h.clientService.CreateRetainer(agency.ID, req.ClientID, req.MonthlyAmount, req.ChurnProbability, req.BillingDay)
 	if err != nil {
 		SendError(c, http.StatusBadRequest, err.Error())
 		return
@@ -47,11 +50,62 @@ func (h *RetainerHandler) CreateRetainer(c *gin.Context) {
 	c.Status(http.StatusCreated)
 }
 
-func (h *RetainerHandler) GetRetainerSummary(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
+func (h *RetainerHandler) ListRetainers(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	retainers, err := h.clientService.ListRetainers(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, retainers)
+}
+
+type UpdateRetainerRequest struct {
+	ChurnProbability *float64 `json:"churn_probability" binding:"omitempty,min=0,max=1"`
+	BillingDay       *int     `json:"billing_day" binding:"omitempty,min=1,max=28"`
+}
+
+func (h *RetainerHandler) UpdateRetainer(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req UpdateRetainerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	if req.ChurnProbability == nil && req.BillingDay == nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: nothing to update")
+		return
+	}
+
+	err := h.clientService.UpdateRetainer(agency.ID, c.Param("id"), services.RetainerUpdate{
+		ChurnProbability: req.ChurnProbability,
+		BillingDay:       req.BillingDay,
+	})
+	if errors.Is(err, services.ErrRetainerNotFound) {
+		SendError(c, http.StatusNotFound, "Retainer not found")
+		return
+	}
 	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+		SendInternalError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RetainerHandler) GetRetainerSummary(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ScenarioHandler struct {
+	agencyService   services.AgencyService
+	scenarioService services.ScenarioService
+}
+
+func NewScenarioHandler(agencyService services.AgencyService, scenarioService services.ScenarioService) *ScenarioHandler {
+	return &ScenarioHandler{
+		agencyService:   agencyService,
+		scenarioService: scenarioService,
+	}
+}
+
+type ScenarioAdjustmentRequest struct {
+	Kind            string   `json:"kind" binding:"required,oneof=add_hire lose_client add_retainer cut_cost"`
+	Label           string   `json:"label"`
+	Amount          *float64 `json:"amount" binding:"omitempty,gt=0"`
+	CapacityHours   *float64 `json:"capacity_hours" binding:"omitempty,gt=0"`
+	ClientID        *string  `json:"client_id" binding:"omitempty,uuid"`
+	RecurringCostID *string  `json:"recurring_cost_id" binding:"omitempty,uuid"`
+	StartDate       string   `json:"start_date" binding:"required,datetime=2006-01-02"`
+}
+
+type ScenarioRequest struct {
+	Name        string                      `json:"name" binding:"required"`
+	Description string                      `json:"description"`
+	Adjustments []ScenarioAdjustmentRequest `json:"adjustments" binding:"dive"`
+}
+
+func (r ScenarioRequest) input() services.ScenarioInput {
+	input := services.ScenarioInput{
+		Name:        r.Name,
+		Description: r.Description,
+		Adjustments: make([]services.ScenarioAdjustment, len(r.Adjustments)),
+	}
+	for i, a := range r.Adjustments {
+		input.Adjustments[i] = services.ScenarioAdjustment{
+			Kind:            a.Kind,
+			Label:           a.Label,
+			Amount:          a.Amount,
+			CapacityHours:   a.CapacityHours,
+			ClientID:        a.ClientID,
+			RecurringCostID: a.RecurringCostID,
+			StartDate:       a.StartDate,
+		}
+	}
+	return input
+}
+
+func (h *ScenarioHandler) ListScenarios(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	scenarios, err := h.scenarioService.ListScenarios(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, scenarios)
+}
+
+func (h *ScenarioHandler) GetScenario(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	scenario, err := h.scenarioService.GetScenario(agency.ID, c.Param("id"))
+	if !handleScenarioError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, scenario)
+}
+
+func (h *ScenarioHandler) CreateScenario(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req ScenarioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	scenario, err := h.scenarioService.CreateScenario(agency.ID, userID, req.input())
+	if !handleScenarioError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, scenario)
+}
+
+// UpdateScenario replaces the scenario's name, description and adjustments.
+func (h *ScenarioHandler) UpdateScenario(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	var req ScenarioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	scenario, err := h.scenarioService.UpdateScenario(agency.ID, c.Param("id"), req.input())
+	if !handleScenarioError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, scenario)
+}
+
+func (h *ScenarioHandler) DeleteScenario(c *gin.Context) {
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	err := h.scenarioService.DeleteScenario(agency.ID, c.Param("id"))
+	if !handleScenarioError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ScenarioHandler) EvaluateScenario(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	months := 12
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid months: expected a number")
+			return
+		}
+		months = n
+	}
+	excludeRestricted := c.Query("exclude_restricted") == "true"
+
+	evaluation, err := h.scenarioService.EvaluateScenario(agency.ID, c.Param("id"), months, excludeRestricted)
+	if !handleScenarioError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
+
+// handleScenarioError writes the response for a failed scenario operation and
+// reports whether the caller should continue.
+func handleScenarioError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrScenarioNotFound):
+		SendError(c, http.StatusNotFound, "Scenario not found")
+	case errors.Is(err, services.ErrInvalidScenario), errors.Is(err, services.ErrInvalidDateRange):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ScoreHistoryHandler struct {
+	agencyService       services.AgencyService
+	scoreHistoryService services.ScoreHistoryService
+}
+
+func NewScoreHistoryHandler(agencyService services.AgencyService, scoreHistoryService services.ScoreHistoryService) *ScoreHistoryHandler {
+	return &ScoreHistoryHandler{
+		agencyService:       agencyService,
+		scoreHistoryService: scoreHistoryService,
+	}
+}
+
+func (h *ScoreHistoryHandler) GetScoreHistory(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	history, err := h.scoreHistoryService.GetScoreHistory(agency.ID, c.Query("from"), c.Query("to"))
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *ScoreHistoryHandler) GetScoreMovers(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	movers, err := h.scoreHistoryService.GetScoreMovers(agency.ID, c.Query("from"), c.Query("to"))
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrNoScoreSnapshot) {
+		SendError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, movers)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ScoringProfileHandler struct {
+	agencyService  services.AgencyService
+	scoringService services.ScoringService
+}
+
+func NewScoringProfileHandler(agencyService services.AgencyService, scoringService services.ScoringService) *ScoringProfileHandler {
+	return &ScoringProfileHandler{
+		agencyService:  agencyService,
+		scoringService: scoringService,
+	}
+}
+
+func (h *ScoringProfileHandler) GetScoringProfile(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	profile, err := h.scoringService.GetScoringProfile(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateScoringProfile replaces the whole profile; fetch the current one to
+// start from.
+func (h *ScoringProfileHandler) UpdateScoringProfile(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req models.ScoringProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	profile, err := h.scoringService.UpdateScoringProfile(agency.ID, userID, req)
+	if errors.Is(err, services.ErrInvalidScoringProfile) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ResetScoringProfile goes back to the built-in defaults.
+func (h *ScoringProfileHandler) ResetScoringProfile(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(string)
+	profile, err := h.scoringService.ResetScoringProfile(agency.ID, userID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
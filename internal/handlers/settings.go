@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	agencyService   services.AgencyService
+	settingsService services.SettingsService
+}
+
+func NewSettingsHandler(agencyService services.AgencyService, settingsService services.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		agencyService:   agencyService,
+		settingsService: settingsService,
+	}
+}
+
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.GetSettings(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+type UpdateSettingsRequest struct {
+	Timezone             *string  `json:"timezone"`
+	FiscalYearStartMonth *int     `json:"fiscal_year_start_month" binding:"omitempty,min=1,max=12"`
+	LookbackDays         *int     `json:"lookback_days" binding:"omitempty,min=1,max=366"`
+	MonthlyCapacityHours *float64 `json:"monthly_capacity_hours" binding:"omitempty,gt=0"`
+	FutureToleranceDays  *int     `json:"future_entry_tolerance_days" binding:"omitempty,min=0,max=31"`
+	BurnAverageMonths    *int     `json:"burn_average_months" binding:"omitempty,oneof=1 3 6"`
+	CashSafetyFloor      *float64 `json:"cash_safety_floor" binding:"omitempty,min=0"`
+}
+
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(agency.ID, services.SettingsUpdate{
+		Timezone:             req.Timezone,
+		FiscalYearStartMonth: req.FiscalYearStartMonth,
+		LookbackDays:         req.LookbackDays,
+		MonthlyCapacityHours: req.MonthlyCapacityHours,
+		FutureToleranceDays:  req.FutureToleranceDays,
+		BurnAverageMonths:    req.BurnAverageMonths,
+		CashSafetyFloor:      req.CashSafetyFloor,
+	})
+	if errors.Is(err, services.ErrInvalidSettings) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -20,14 +20,15 @@ func NewSurvivalHandler(agencyService services.AgencyService, financeService ser
 }
 
 func (h *SurvivalHandler) GetBurnRunway(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
-	metrics, err := h.financeService.GetSurvivalMetrics(agency.ID)
+	// Restricted accounts (e.g. a tax reserve) can be left out of runway
+	excludeRestricted := c.Query("exclude_restricted") == "true"
+
+	metrics, err := h.financeService.GetSurvivalMetrics(agency.ID, excludeRestricted)
 	if err != nil {
 		SendInternalError(c)
 		return
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/agency-finance-reality/server/internal/services"
@@ -22,13 +23,12 @@ func NewUtilizationHandler(agencyService services.AgencyService, utilizationServ
 type AddTimeEntryRequest struct {
 	ClientID *string `json:"client_id"`
 	Hours    float64 `json:"hours" binding:"required,gt=0"`
+	Date     string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
 }
 
 func (h *UtilizationHandler) AddTimeEntry(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := writableAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -38,7 +38,11 @@ func (h *UtilizationHandler) AddTimeEntry(c *gin.Context) {
 		return
 	}
 
-	err = h.utilizationService.AddTimeEntry(agency.ID, req.ClientID, req.Hours)
+	err := h.utilizationService.AddTimeEntry(agency.ID, req.ClientID, req.Date, req.Hours)
+	if errors.Is(err, services.ErrInvalidEntryDate) {
+		SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		SendInternalError(c)
 		return
@@ -48,10 +52,8 @@ func (h *UtilizationHandler) AddTimeEntry(c *gin.Context) {
 }
 
 func (h *UtilizationHandler) GetUtilization(c *gin.Context) {
-	userID := c.MustGet("user_id").(string)
-	agency, err := h.agencyService.GetAgencyByUserID(userID)
-	if err != nil {
-		SendError(c, http.StatusNotFound, "Agency not found")
+	agency, ok := currentAgency(c, h.agencyService)
+	if !ok {
 		return
 	}
 
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/agency-finance-reality/server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	agencyService  services.AgencyService
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(agencyService services.AgencyService, webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		agencyService:  agencyService,
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(agency.ID)
+	if err != nil {
+		SendInternalError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+// CreateWebhook responds with the signing secret, which is only shown here.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(agency.ID, services.WebhookInput{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+	})
+	if !handleWebhookError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SendError(c, http.StatusBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(agency.ID, c.Param("id"), services.WebhookUpdate{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Enabled:    req.Enabled,
+	})
+	if !handleWebhookError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	err := h.webhookService.DeleteWebhook(agency.ID, c.Param("id"))
+	if !handleWebhookError(c, err) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns the webhook's delivery log, newest first, up to
+// ?limit= entries.
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid limit: expected a number")
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(agency.ID, c.Param("id"), limit)
+	if !handleWebhookError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver sends a logged delivery's event again and responds with the new
+// delivery, whether or not it succeeded.
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	agency, ok := agencyWithRole(c, h.agencyService, services.RoleOwner)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(agency.ID, c.Param("id"), c.Param("delivery_id"))
+	if !handleWebhookError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, delivery)
+}
+
+// handleWebhookError writes the response for a failed webhook operation and
+// reports whether the caller should continue.
+func handleWebhookError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrWebhookNotFound):
+		SendError(c, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		SendError(c, http.StatusNotFound, "Webhook delivery not found")
+	case errors.Is(err, services.ErrInvalidWebhook), errors.Is(err, services.ErrInvalidDateRange):
+		SendError(c, http.StatusBadRequest, err.Error())
+	default:
+		SendInternalError(c)
+	}
+	return false
+}
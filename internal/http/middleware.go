@@ -8,25 +8,35 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
+func AuthMiddleware(authenticator auth.Authenticator, authService services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := c.GetHeader("CF-Access-Jwt-Assertion")
-
-		// Validate using auth package
-		sub, email, err := auth.ValidateToken(tokenString)
+		identity, err := authenticator.Authenticate(c.Request)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 			return
 		}
 
-		// User Auto-Provisioning
-		if err := authService.EnsureUser(sub, email); err != nil {
+		// User Auto-Provisioning (single path for every provider)
+		if err := authService.EnsureUser(identity.Subject, identity.Email); err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 			return
 		}
 
-		c.Set("user_id", sub)
-		c.Set("email", email)
+		// Read-only API tokens may only use safe methods
+		if identity.Scope == auth.ScopeRead && !isReadOnlyMethod(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token scope does not allow writes"})
+			return
+		}
+
+		c.Set("user_id", identity.Subject)
+		c.Set("email", identity.Email)
+		if identity.APITokenID != "" {
+			c.Set("api_token_id", identity.APITokenID)
+		}
 		c.Next()
 	}
 }
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -2,14 +2,23 @@ package http
 
 import (
 	"database/sql"
+	"time"
 
+	"github.com/agency-finance-reality/server/internal/auth"
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
 	"github.com/agency-finance-reality/server/internal/handlers"
+	"github.com/agency-finance-reality/server/internal/jobs"
+	"github.com/agency-finance-reality/server/internal/notify"
 	"github.com/agency-finance-reality/server/internal/repository"
 	"github.com/agency-finance-reality/server/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(db *sql.DB) *gin.Engine {
+// NewRouter wires the API and registers its background jobs on scheduler; the
+// caller is responsible for running the scheduler. Alert rules can deliver
+// through the given channels; outgoing webhooks are posted with webhook.
+func NewRouter(db *sql.DB, authenticator auth.Authenticator, scheduler *jobs.Scheduler, channels map[string]notify.Channel, webhook *notify.Webhook) *gin.Engine {
 	// Repositories
 	founderRepo := repository.NewFounderRepository(db)
 	agencyRepo := repository.NewAgencyRepository(db)
@@ -18,23 +27,86 @@ func NewRouter(db *sql.DB) *gin.Engine {
 	clientRepo := repository.NewClientRepository(db)
 	retainerRepo := repository.NewRetainerRepository(db)
 	timeRepo := repository.NewTimeEntryRepository(db)
+	tokenRepo := repository.NewAPITokenRepository(db)
+	memberRepo := repository.NewMemberRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	bankRepo := repository.NewBankAccountRepository(db)
+	recurringRepo := repository.NewRecurringCostRepository(db)
+	plannedRepo := repository.NewPlannedItemRepository(db)
+	scenarioRepo := repository.NewScenarioRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	cashFlowWeekRepo := repository.NewCashFlowWeekRepository(db)
+	scoringRepo := repository.NewScoringProfileRepository(db)
+	scoreSnapshotRepo := repository.NewScoreSnapshotRepository(db)
+	alertRuleRepo := repository.NewAlertRuleRepository(db)
+	alertRepo := repository.NewAlertRepository(db)
+	scoreStatusRepo := repository.NewScoreStatusRepository(db)
+	webhookRepo := repository.NewWebhookSubscriptionRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+
+	clk := clock.Real{}
+	bus := events.NewBus()
 
 	// Services
-	authService := services.NewAuthService(founderRepo)
-	agencyService := services.NewAgencyService(agencyRepo)
-	financeService := services.NewFinanceService(cashRepo, financeRepo, retainerRepo, timeRepo)
-	clientService := services.NewClientService(clientRepo, retainerRepo, financeRepo)
-	utilizationService := services.NewUtilizationService(timeRepo)
+	authService := services.NewAuthService(founderRepo, memberRepo)
+	agencyService := services.NewAgencyService(agencyRepo, memberRepo, clk)
+	financeService := services.NewFinanceService(agencyRepo, cashRepo, bankRepo, financeRepo, recurringRepo, retainerRepo, timeRepo, settingsRepo, scoringRepo, scoreStatusRepo, bus, clk)
+	clientService := services.NewClientService(clientRepo, retainerRepo, financeRepo, recurringRepo, settingsRepo, bus, clk)
+	utilizationService := services.NewUtilizationService(agencyRepo, timeRepo, settingsRepo, bus, clk)
+	tokenService := services.NewAPITokenService(tokenRepo)
+	settingsService := services.NewSettingsService(settingsRepo)
+	bankAccountService := services.NewBankAccountService(agencyRepo, bankRepo, cashRepo, settingsRepo, bus, clk)
+	recurringCostService := services.NewRecurringCostService(agencyRepo, recurringRepo, financeRepo, settingsRepo, bus, clk)
+	forecastService := services.NewForecastService(agencyRepo, bankRepo, cashRepo, financeRepo, retainerRepo, recurringRepo, plannedRepo, invoiceRepo, settingsRepo, clk)
+	scenarioService := services.NewScenarioService(scenarioRepo, clientRepo, bankRepo, cashRepo, financeRepo, recurringRepo, retainerRepo, plannedRepo, invoiceRepo, timeRepo, settingsRepo, scoringRepo, clk)
+	invoiceService := services.NewInvoiceService(invoiceRepo, clientRepo)
+	scoringService := services.NewScoringService(scoringRepo)
+	scoreHistoryService := services.NewScoreHistoryService(agencyRepo, scoreSnapshotRepo, bankRepo, cashRepo, financeRepo, recurringRepo, retainerRepo, timeRepo, settingsRepo, scoringRepo, clk)
+	cashFlowService := services.NewCashFlowService(agencyRepo, bankRepo, cashRepo, cashFlowWeekRepo, financeRepo, retainerRepo, recurringRepo, plannedRepo, invoiceRepo, settingsRepo, clk)
+	alertService := services.NewAlertService(agencyRepo, alertRuleRepo, alertRepo, bankRepo, cashRepo, financeRepo, recurringRepo, retainerRepo, timeRepo, settingsRepo, channels, clk)
+	webhookService := services.NewWebhookService(webhookRepo, webhookDeliveryRepo, webhook, clk)
+
+	// Event subscribers
+	bus.Subscribe("evaluate-alert-rules", func(e events.Event) error {
+		return alertService.Evaluate(e.AgencyID)
+	})
+	bus.Subscribe("refresh-score-status", func(e events.Event) error {
+		if e.Type == events.ScoreStatusChanged {
+			return nil
+		}
+		return financeService.RefreshScoreStatus(e.AgencyID)
+	})
+	bus.Subscribe("dispatch-webhooks", webhookService.Dispatch)
+
+	// Background jobs
+	scheduler.Register("post-recurring-costs", time.Hour, recurringCostService.PostDueCosts)
+	scheduler.Register("snapshot-reality-scores", time.Hour, scoreHistoryService.SnapshotScores)
+	scheduler.Register("evaluate-alert-rules", time.Hour, alertService.EvaluateAll)
+	scheduler.Register("refresh-score-statuses", time.Hour, financeService.RefreshScoreStatuses)
+	scheduler.Register("freeze-cash-flow-forecasts", time.Hour, cashFlowService.FreezeForecasts)
+	scheduler.Register("retry-webhook-deliveries", time.Minute, webhookService.RetryDue)
 
 	// Handlers
 	agencyHandler := handlers.NewAgencyHandler(agencyService)
 	cashHandler := handlers.NewCashSnapshotHandler(agencyService, financeService)
 	financeHandler := handlers.NewDailyFinanceHandler(agencyService, financeService)
 	realityScoreHandler := handlers.NewRealityScoreHandler(agencyService, financeService)
+	scoringProfileHandler := handlers.NewScoringProfileHandler(agencyService, scoringService)
+	scoreHistoryHandler := handlers.NewScoreHistoryHandler(agencyService, scoreHistoryService)
 	clientHandler := handlers.NewClientHandler(agencyService, clientService)
 	retainerHandler := handlers.NewRetainerHandler(agencyService, clientService)
 	utilizationHandler := handlers.NewUtilizationHandler(agencyService, utilizationService)
 	survivalHandler := handlers.NewSurvivalHandler(agencyService, financeService)
+	tokenHandler := handlers.NewAPITokenHandler(tokenService)
+	settingsHandler := handlers.NewSettingsHandler(agencyService, settingsService)
+	bankAccountHandler := handlers.NewBankAccountHandler(agencyService, bankAccountService)
+	recurringCostHandler := handlers.NewRecurringCostHandler(agencyService, recurringCostService)
+	forecastHandler := handlers.NewForecastHandler(agencyService, forecastService)
+	scenarioHandler := handlers.NewScenarioHandler(agencyService, scenarioService)
+	invoiceHandler := handlers.NewInvoiceHandler(agencyService, invoiceService)
+	cashFlowHandler := handlers.NewCashFlowHandler(agencyService, cashFlowService)
+	alertHandler := handlers.NewAlertHandler(agencyService, alertService)
+	webhookHandler := handlers.NewWebhookHandler(agencyService, webhookService)
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -44,30 +116,98 @@ func NewRouter(db *sql.DB) *gin.Engine {
 
 	// Private
 	api := r.Group("/")
-	api.Use(AuthMiddleware(authService))
+	api.Use(AuthMiddleware(auth.Chain{auth.NewAPITokenAuthenticator(tokenService), authenticator}, authService))
 
+	api.GET("/api-tokens", tokenHandler.ListTokens)
+	api.POST("/api-tokens", tokenHandler.CreateToken)
+	api.DELETE("/api-tokens/:id", tokenHandler.RevokeToken)
+
+	api.GET("/agencies", agencyHandler.ListAgencies)
 	api.GET("/agency", agencyHandler.GetAgency)
 	api.POST("/agency", agencyHandler.CreateAgency)
+	api.GET("/agency/members", agencyHandler.GetMembers)
+	api.GET("/agency/invitations", agencyHandler.GetInvitations)
+	api.POST("/agency/invitations", agencyHandler.InviteMember)
+	api.GET("/agency/settings", settingsHandler.GetSettings)
+	api.PATCH("/agency/settings", settingsHandler.UpdateSettings)
 
 	api.GET("/cash-snapshot/today", cashHandler.GetTodaysCash)
 	api.POST("/cash-snapshot", cashHandler.RecordDailyCash)
+	api.GET("/cash-snapshot/:date", cashHandler.GetCashOnDate)
+	api.PUT("/cash-snapshot/:date", cashHandler.PutCashOnDate)
+	api.GET("/cash-snapshots", cashHandler.GetCashHistory)
+
+	api.GET("/bank-accounts", bankAccountHandler.ListAccounts)
+	api.POST("/bank-accounts", bankAccountHandler.CreateAccount)
+	api.PATCH("/bank-accounts/:id", bankAccountHandler.UpdateAccount)
+	api.DELETE("/bank-accounts/:id", bankAccountHandler.ArchiveAccount)
+	api.POST("/bank-accounts/:id/snapshots", bankAccountHandler.RecordSnapshot)
 
 	api.POST("/revenue", financeHandler.AddRevenue)
 	api.POST("/cost", financeHandler.AddCost)
+	api.GET("/revenues", financeHandler.ListRevenues)
+	api.PATCH("/revenues/:id", financeHandler.UpdateRevenue)
+	api.DELETE("/revenues/:id", financeHandler.DeleteRevenue)
+	api.GET("/costs", financeHandler.ListCosts)
+	api.PATCH("/costs/:id", financeHandler.UpdateCost)
+	api.DELETE("/costs/:id", financeHandler.DeleteCost)
+	api.GET("/recurring-costs", recurringCostHandler.ListRecurringCosts)
+	api.POST("/recurring-costs", recurringCostHandler.CreateRecurringCost)
+	api.GET("/recurring-costs/upcoming", recurringCostHandler.ListUpcoming)
+	api.PATCH("/recurring-costs/:id", recurringCostHandler.UpdateRecurringCost)
+	api.DELETE("/recurring-costs/:id", recurringCostHandler.DeleteRecurringCost)
 	api.GET("/daily-summary/today", financeHandler.GetDailySummary)
 	api.GET("/cost-breakdown", financeHandler.GetCostBreakdown)
 
 	api.GET("/burn-runway", survivalHandler.GetBurnRunway)
+	api.GET("/forecast/cash", forecastHandler.GetCashForecast)
+	api.GET("/forecast/runway-distribution", forecastHandler.GetRunwayDistribution)
+	api.GET("/forecast/planned-items", forecastHandler.ListPlannedItems)
+	api.POST("/forecast/planned-items", forecastHandler.CreatePlannedItem)
+	api.DELETE("/forecast/planned-items/:id", forecastHandler.DeletePlannedItem)
+	api.GET("/reports/cash-flow-13-week", cashFlowHandler.GetThirteenWeekReport)
+	api.PUT("/reports/cash-flow-13-week/actuals/:week_start", cashFlowHandler.RecordWeekActual)
+
+	api.GET("/scenarios", scenarioHandler.ListScenarios)
+	api.POST("/scenarios", scenarioHandler.CreateScenario)
+	api.GET("/scenarios/:id", scenarioHandler.GetScenario)
+	api.PUT("/scenarios/:id", scenarioHandler.UpdateScenario)
+	api.DELETE("/scenarios/:id", scenarioHandler.DeleteScenario)
+	api.GET("/scenarios/:id/evaluate", scenarioHandler.EvaluateScenario)
 
 	api.POST("/clients", clientHandler.CreateClient)
 	api.GET("/clients", clientHandler.GetClients)
+	api.GET("/retainers", retainerHandler.ListRetainers)
 	api.POST("/retainers", retainerHandler.CreateRetainer)
+	api.PATCH("/retainers/:id", retainerHandler.UpdateRetainer)
 	api.GET("/retainer-summary", retainerHandler.GetRetainerSummary)
+	api.GET("/invoices", invoiceHandler.ListInvoices)
+	api.POST("/invoices", invoiceHandler.CreateInvoice)
+	api.PATCH("/invoices/:id", invoiceHandler.UpdateInvoice)
+	api.DELETE("/invoices/:id", invoiceHandler.DeleteInvoice)
 
 	api.POST("/time-entry", utilizationHandler.AddTimeEntry)
 	api.GET("/utilization", utilizationHandler.GetUtilization)
 
 	api.GET("/agency-reality-score", realityScoreHandler.GetRealityScore)
+	api.GET("/agency-reality-score/history", scoreHistoryHandler.GetScoreHistory)
+	api.GET("/agency-reality-score/movers", scoreHistoryHandler.GetScoreMovers)
+	api.GET("/agency/scoring-profile", scoringProfileHandler.GetScoringProfile)
+	api.PUT("/agency/scoring-profile", scoringProfileHandler.UpdateScoringProfile)
+	api.DELETE("/agency/scoring-profile", scoringProfileHandler.ResetScoringProfile)
+
+	api.GET("/alert-rules", alertHandler.ListRules)
+	api.POST("/alert-rules", alertHandler.CreateRule)
+	api.PUT("/alert-rules/:id", alertHandler.UpdateRule)
+	api.DELETE("/alert-rules/:id", alertHandler.DeleteRule)
+	api.GET("/alerts", alertHandler.ListAlerts)
+
+	api.GET("/webhooks", webhookHandler.ListWebhooks)
+	api.POST("/webhooks", webhookHandler.CreateWebhook)
+	api.PATCH("/webhooks/:id", webhookHandler.UpdateWebhook)
+	api.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+	api.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	api.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
 
 	return r
 }
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a background task run on a fixed interval inside the server process.
+// Jobs must be idempotent: every instance of the server runs them, and a run
+// may repeat work an earlier one already did.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs []Job
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Register(name string, interval time.Duration, run func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+// Run starts every registered job, runs each once immediately and then on its
+// interval, until stop is closed. Failures are logged and retried on the next
+// tick.
+func (s *Scheduler) Run(stop <-chan struct{}) {
+	s.mu.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			runJob(job, stop)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func runJob(job Job, stop <-chan struct{}) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(); err != nil {
+			log.Printf("Job %s failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -7,15 +7,59 @@ type AgencyView struct {
 	ID           string    `json:"id"`
 	Name         string    `json:"name"`
 	BaseCurrency string    `json:"base_currency"`
+	Role         string    `json:"role"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type MemberView struct {
+	FounderID string    `json:"founder_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InvitationView struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // Finance models
 type DailySnapshotView struct {
-	Date                string   `json:"date"`
-	CashBalance         float64  `json:"cash_balance"`
-	PreviousCashBalance *float64 `json:"previous_cash_balance"`
-	Delta               *float64 `json:"delta"`
+	Date                string                     `json:"date"`
+	CashBalance         float64                    `json:"cash_balance"`
+	PreviousCashBalance *float64                   `json:"previous_cash_balance"`
+	Delta               *float64                   `json:"delta"`
+	Revisions           []CashSnapshotRevisionView `json:"revisions"`
+}
+
+type CashSnapshotRevisionView struct {
+	PreviousBalance float64   `json:"previous_balance"`
+	NewBalance      float64   `json:"new_balance"`
+	ChangedBy       *string   `json:"changed_by"`
+	ChangedAt       time.Time `json:"changed_at"`
+}
+
+type CashHistoryPoint struct {
+	PeriodStart  string   `json:"period_start"`
+	PeriodEnd    string   `json:"period_end"`
+	Open         *float64 `json:"open"`
+	Close        *float64 `json:"close"`
+	Min          *float64 `json:"min"`
+	Max          *float64 `json:"max"`
+	Avg          *float64 `json:"avg"`
+	RecordedDays int      `json:"recorded_days"`
+	Delta        *float64 `json:"delta"`
+	DeltaPercent *float64 `json:"delta_percent"`
+}
+
+type CashHistoryView struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Interval string             `json:"interval"`
+	Points   []CashHistoryPoint `json:"points"`
 }
 
 type DailySummaryView struct {
@@ -25,6 +69,50 @@ type DailySummaryView struct {
 	Net     float64 `json:"net"`
 }
 
+type RevenueView struct {
+	ID        string    `json:"id"`
+	Date      string    `json:"date"`
+	Amount    float64   `json:"amount"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeletedView identifies a record that has been deleted.
+type DeletedView struct {
+	ID string `json:"id"`
+}
+
+type CostView struct {
+	ID              string    `json:"id"`
+	Date            string    `json:"date"`
+	Amount          float64   `json:"amount"`
+	Type            string    `json:"type"`
+	Label           string    `json:"label"`
+	Category        string    `json:"category"`
+	RecurringCostID *string   `json:"recurring_cost_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type RecurringCostView struct {
+	ID            string  `json:"id"`
+	Amount        float64 `json:"amount"`
+	Label         string  `json:"label"`
+	Category      string  `json:"category"`
+	Cadence       string  `json:"cadence"`
+	StartDate     string  `json:"start_date"`
+	EndDate       *string `json:"end_date"`
+	PostedThrough *string `json:"posted_through"`
+	NextDate      *string `json:"next_date"`
+}
+
+type UpcomingCostView struct {
+	RecurringCostID string  `json:"recurring_cost_id"`
+	Date            string  `json:"date"`
+	Amount          float64 `json:"amount"`
+	Label           string  `json:"label"`
+	Category        string  `json:"category"`
+}
+
 type CostDriver struct {
 	Category   string  `json:"category"`
 	Amount     float64 `json:"amount"`
@@ -38,11 +126,189 @@ type CostBreakdownView struct {
 }
 
 type SurvivalMetricsView struct {
-	CashBalance     float64  `json:"cash_balance"`
-	MonthlyBurn     float64  `json:"monthly_burn"`
-	RunwayMonths    *float64 `json:"runway_months"`
-	OperatingMargin float64  `json:"operating_margin"`
-	TotalRetainers  float64  `json:"total_retainers"`
+	CashBalance        float64 `json:"cash_balance"`
+	RestrictedCash     float64 `json:"restricted_cash"`
+	AvailableCash      float64 `json:"available_cash"`
+	ExcludesRestricted bool    `json:"excludes_restricted"`
+	// MonthlyBurn is recurring commitments plus the trailing average of
+	// one-off fixed costs; RawMonthlyBurn is fixed costs posted in the
+	// lookback window.
+	MonthlyBurn       float64  `json:"monthly_burn"`
+	RecurringBurn     float64  `json:"recurring_burn"`
+	AverageOneOffBurn float64  `json:"average_one_off_burn"`
+	BurnAverageMonths int      `json:"burn_average_months"`
+	RawMonthlyBurn    float64  `json:"raw_monthly_burn"`
+	RunwayMonths      *float64 `json:"runway_months"`
+	OperatingMargin   float64  `json:"operating_margin"`
+	TotalRetainers    float64  `json:"total_retainers"`
+}
+
+// Bank account models
+type BankAccountView struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Currency      string   `json:"currency"`
+	Restricted    bool     `json:"restricted"`
+	LatestDate    *string  `json:"latest_date"`
+	LatestBalance *float64 `json:"latest_balance"`
+	ExchangeRate  *float64 `json:"exchange_rate"`
+	BaseBalance   *float64 `json:"base_balance"`
+}
+
+type BankAccountSnapshotView struct {
+	AccountID           string  `json:"account_id"`
+	Date                string  `json:"date"`
+	Balance             float64 `json:"balance"`
+	ExchangeRate        float64 `json:"exchange_rate"`
+	BaseBalance         float64 `json:"base_balance"`
+	ConsolidatedBalance float64 `json:"consolidated_balance"`
+}
+
+// Forecast models
+type PlannedItemView struct {
+	ID        string  `json:"id"`
+	Date      string  `json:"date"`
+	Amount    float64 `json:"amount"`
+	Direction string  `json:"direction"`
+	Label     string  `json:"label"`
+}
+
+type CashForecastMonth struct {
+	Month          string  `json:"month"`
+	OpeningBalance float64 `json:"opening_balance"`
+	Inflows        float64 `json:"inflows"`
+	Outflows       float64 `json:"outflows"`
+	ClosingBalance float64 `json:"closing_balance"`
+	LowestBalance  float64 `json:"lowest_balance"`
+}
+
+type CashForecastView struct {
+	StartDate          string              `json:"start_date"`
+	EndDate            string              `json:"end_date"`
+	StartingBalance    float64             `json:"starting_balance"`
+	ExcludesRestricted bool                `json:"excludes_restricted"`
+	SafetyFloor        float64             `json:"safety_floor"`
+	Months             []CashForecastMonth `json:"months"`
+	ZeroCashDate       *string             `json:"zero_cash_date"`
+	BelowFloorDate     *string             `json:"below_floor_date"`
+	BelowFloorMonth    *string             `json:"below_floor_month"`
+}
+
+type BelowZeroProbabilityView struct {
+	Within3Months  float64 `json:"within_3_months"`
+	Within6Months  float64 `json:"within_6_months"`
+	Within12Months float64 `json:"within_12_months"`
+}
+
+// RunwayDistributionView summarizes simulated runway. Paths that never run out
+// of cash count as HorizonMonths.
+type RunwayDistributionView struct {
+	Seed                 int64                    `json:"seed"`
+	Simulations          int                      `json:"simulations"`
+	HorizonMonths        int                      `json:"horizon_months"`
+	StartingCash         float64                  `json:"starting_cash"`
+	ExcludesRestricted   bool                     `json:"excludes_restricted"`
+	HistoryMonths        int                      `json:"history_months"`
+	MonthlyRevenueMean   float64                  `json:"monthly_revenue_mean"`
+	MonthlyRevenueStdDev float64                  `json:"monthly_revenue_std_dev"`
+	MonthlyCostsMean     float64                  `json:"monthly_costs_mean"`
+	MonthlyCostsStdDev   float64                  `json:"monthly_costs_std_dev"`
+	RunwayP10Months      float64                  `json:"runway_p10_months"`
+	RunwayP50Months      float64                  `json:"runway_p50_months"`
+	RunwayP90Months      float64                  `json:"runway_p90_months"`
+	ProbabilityBelowZero BelowZeroProbabilityView `json:"probability_below_zero"`
+}
+
+type InvoiceView struct {
+	ID       string  `json:"id"`
+	ClientID *string `json:"client_id"`
+	Number   string  `json:"number"`
+	Amount   float64 `json:"amount"`
+	DueDate  string  `json:"due_date"`
+	PaidOn   *string `json:"paid_on"`
+}
+
+// 13-week cash flow models
+
+type CashFlowInflowsView struct {
+	Retainers float64 `json:"retainers"`
+	Invoices  float64 `json:"invoices"`
+	Planned   float64 `json:"planned"`
+	Total     float64 `json:"total"`
+}
+
+type CashFlowOutflowsView struct {
+	People float64 `json:"people"`
+	Tools  float64 `json:"tools"`
+	Other  float64 `json:"other"`
+	Total  float64 `json:"total"`
+}
+
+type CashFlowWeekView struct {
+	Week           int                  `json:"week"`
+	WeekStart      string               `json:"week_start"`
+	WeekEnd        string               `json:"week_end"`
+	OpeningBalance float64              `json:"opening_balance"`
+	Inflows        CashFlowInflowsView  `json:"inflows"`
+	Outflows       CashFlowOutflowsView `json:"outflows"`
+	NetCashFlow    float64              `json:"net_cash_flow"`
+	ClosingBalance float64              `json:"closing_balance"`
+}
+
+// CashFlowVarianceView compares recorded actuals with the forecast that stood
+// when the week began. Forecast and variance fields are nil when no report was
+// generated before the week started. Variance is actual minus forecast.
+type CashFlowVarianceView struct {
+	WeekStart        string   `json:"week_start"`
+	WeekEnd          string   `json:"week_end"`
+	ForecastInflows  *float64 `json:"forecast_inflows"`
+	ActualInflows    float64  `json:"actual_inflows"`
+	InflowVariance   *float64 `json:"inflow_variance"`
+	ForecastOutflows *float64 `json:"forecast_outflows"`
+	ActualOutflows   float64  `json:"actual_outflows"`
+	OutflowVariance  *float64 `json:"outflow_variance"`
+	ForecastClosing  *float64 `json:"forecast_closing"`
+	ActualClosing    float64  `json:"actual_closing"`
+	ClosingVariance  *float64 `json:"closing_variance"`
+}
+
+type CashFlowReportView struct {
+	AsOf           string                 `json:"as_of"`
+	CurrentBalance float64                `json:"current_balance"`
+	Weeks          []CashFlowWeekView     `json:"weeks"`
+	Variance       []CashFlowVarianceView `json:"variance"`
+}
+
+// Scenario models
+type ScenarioAdjustmentView struct {
+	Kind            string   `json:"kind"`
+	Label           string   `json:"label"`
+	Amount          *float64 `json:"amount,omitempty"`
+	CapacityHours   *float64 `json:"capacity_hours,omitempty"`
+	ClientID        *string  `json:"client_id,omitempty"`
+	RecurringCostID *string  `json:"recurring_cost_id,omitempty"`
+	StartDate       string   `json:"start_date"`
+}
+
+type ScenarioView struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Adjustments []ScenarioAdjustmentView `json:"adjustments"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+type ScenarioOutcomeView struct {
+	Survival     *SurvivalMetricsView `json:"survival"`
+	Forecast     *CashForecastView    `json:"forecast"`
+	RealityScore *RealityScoreView    `json:"reality_score"`
+}
+
+type ScenarioEvaluationView struct {
+	Scenario  ScenarioView        `json:"scenario"`
+	Baseline  ScenarioOutcomeView `json:"baseline"`
+	Projected ScenarioOutcomeView `json:"projected"`
 }
 
 // Client models
@@ -52,6 +318,14 @@ type ClientView struct {
 	Status string `json:"status"`
 }
 
+type RetainerView struct {
+	ID               string  `json:"id"`
+	ClientID         string  `json:"client_id"`
+	MonthlyAmount    float64 `json:"monthly_amount"`
+	ChurnProbability float64 `json:"churn_probability"`
+	BillingDay       int     `json:"billing_day"`
+}
+
 type RetainerSummaryView struct {
 	TotalRetainerRevenue float64 `json:"total_retainer_revenue"`
 	FixedCosts           float64 `json:"fixed_costs"`
@@ -82,4 +356,244 @@ type RealityScoreView struct {
 	CashOnHand         float64            `json:"cash_on_hand"`
 	CommittedRetainers float64            `json:"committed_retainers"`
 	PrimaryRisk        string             `json:"primary_risk"`
+	// ProfileVersion names the scoring profile the score was computed with
+	ProfileVersion string `json:"profile_version"`
+	// MissingInputs is non-empty when Status is insufficient_data
+	MissingInputs []string             `json:"missing_inputs"`
+	Components    []ScoreComponentView `json:"components"`
+	Actions       []ScoreActionView    `json:"actions"`
+}
+
+// ScoreComponentView explains one component's points. Value is nil when the
+// measurement can't be taken, such as runway with no burn. NextBand is the
+// closest band worth more points, and DeltaToNextBand the change in Value
+// that reaches its boundary; both are nil when no band scores higher.
+type ScoreComponentView struct {
+	Component       string     `json:"component"`
+	Measure         string     `json:"measure"`
+	Value           *float64   `json:"value"`
+	Points          int        `json:"points"`
+	PointsAvailable int        `json:"points_available"`
+	Band            *ScoreBand `json:"band"`
+	NextBand        *ScoreBand `json:"next_band"`
+	DeltaToNextBand *float64   `json:"delta_to_next_band"`
+}
+
+// ScoreActionView is a concrete change that would move a component into its
+// next band. Amount is in the base currency, except for capacity actions
+// where it is hours per month. Actions are ranked by PointGain and are
+// evaluated independently of each other.
+type ScoreActionView struct {
+	Component string  `json:"component"`
+	Action    string  `json:"action"`
+	Amount    float64 `json:"amount"`
+	PointGain int     `json:"point_gain"`
+}
+
+// ScoreInputsView is what a reality score was computed from. Revenue, costs
+// and used hours cover the lookback window.
+type ScoreInputsView struct {
+	CashOnHand      *float64 `json:"cash_on_hand"`
+	MonthlyBurn     float64  `json:"monthly_burn"`
+	TotalRetainers  float64  `json:"total_retainers"`
+	LargestRetainer float64  `json:"largest_retainer"`
+	Revenue         float64  `json:"revenue"`
+	Costs           float64  `json:"costs"`
+	UsedHours       float64  `json:"used_hours"`
+	CapacityHours   float64  `json:"capacity_hours"`
+}
+
+type ScoreSnapshotView struct {
+	Date           string             `json:"date"`
+	Score          int                `json:"score"`
+	Breakdown      ScoreBreakdownView `json:"breakdown"`
+	Status         string             `json:"status"`
+	PrimaryRisk    string             `json:"primary_risk"`
+	ProfileVersion string             `json:"profile_version"`
+	Inputs         ScoreInputsView    `json:"inputs"`
+}
+
+type ScoreHistoryView struct {
+	From      string              `json:"from"`
+	To        string              `json:"to"`
+	Snapshots []ScoreSnapshotView `json:"snapshots"`
+}
+
+type ScoreComponentMoveView struct {
+	Component string `json:"component"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Change    int    `json:"change"`
+}
+
+// ScoreMoversView compares the latest snapshots on or before two dates.
+// Components are ordered by the size of their change, largest first.
+type ScoreMoversView struct {
+	From           ScoreSnapshotView        `json:"from"`
+	To             ScoreSnapshotView        `json:"to"`
+	ScoreChange    int                      `json:"score_change"`
+	ProfileChanged bool                     `json:"profile_changed"`
+	Components     []ScoreComponentMoveView `json:"components"`
+}
+
+// ScoreBand awards Points when a component's measurement meets every bound
+// that is set. A component scores the first band that matches, or zero.
+type ScoreBand struct {
+	Points  int      `json:"points"`
+	AtLeast *float64 `json:"at_least,omitempty"`
+	Above   *float64 `json:"above,omitempty"`
+	Below   *float64 `json:"below,omitempty"`
+	AtMost  *float64 `json:"at_most,omitempty"`
+}
+
+// ScoreComponentProfile caps a component at Weight points.
+type ScoreComponentProfile struct {
+	Weight int         `json:"weight"`
+	Bands  []ScoreBand `json:"bands"`
+}
+
+// StatusCutoffs are the lowest scores for each status; anything below AtRisk
+// is Danger.
+type StatusCutoffs struct {
+	Healthy int `json:"healthy"`
+	Watch   int `json:"watch"`
+	AtRisk  int `json:"at_risk"`
+}
+
+// ScoringProfile configures the reality score. Bands measure retainer
+// coverage as a ratio of monthly burn, runway in months, the largest
+// retainer's percentage of all retainers, margin percentage over the lookback
+// window and capacity utilization percentage.
+type ScoringProfile struct {
+	RetainerSafety      ScoreComponentProfile `json:"retainer_safety"`
+	Runway              ScoreComponentProfile `json:"runway"`
+	ClientConcentration ScoreComponentProfile `json:"client_concentration"`
+	Profitability       ScoreComponentProfile `json:"profitability"`
+	CapacityPressure    ScoreComponentProfile `json:"capacity_pressure"`
+	StatusCutoffs       StatusCutoffs         `json:"status_cutoffs"`
+}
+
+type ScoringProfileView struct {
+	Version   string         `json:"version"`
+	IsDefault bool           `json:"is_default"`
+	Profile   ScoringProfile `json:"profile"`
+	UpdatedAt *time.Time     `json:"updated_at"`
+}
+
+// API token models
+type APITokenView struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatedAPITokenView struct {
+	APITokenView
+	Token string `json:"token"`
+}
+
+// Settings models
+type AgencySettingsView struct {
+	Timezone             string  `json:"timezone"`
+	FiscalYearStartMonth int     `json:"fiscal_year_start_month"`
+	LookbackDays         int     `json:"lookback_days"`
+	MonthlyCapacityHours float64 `json:"monthly_capacity_hours"`
+	FutureToleranceDays  int     `json:"future_entry_tolerance_days"`
+	BurnAverageMonths    int     `json:"burn_average_months"`
+	CashSafetyFloor      float64 `json:"cash_safety_floor"`
+}
+
+// Alert models
+
+// AlertRuleView is a rule watching a reality score measurement. Breached is
+// true while a breach it has alerted on lasts.
+type AlertRuleView struct {
+	ID              string     `json:"id"`
+	Metric          string     `json:"metric"`
+	Min             *float64   `json:"min"`
+	Max             *float64   `json:"max"`
+	Channel         string     `json:"channel"`
+	Target          string     `json:"target"`
+	CooldownMinutes int        `json:"cooldown_minutes"`
+	Enabled         bool       `json:"enabled"`
+	Breached        bool       `json:"breached"`
+	LastFiredAt     *time.Time `json:"last_fired_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AlertView is an alert a rule fired. DeliveredAt is nil until delivery
+// succeeds; DeliveryError holds the reason it didn't.
+type AlertView struct {
+	ID            string     `json:"id"`
+	RuleID        *string    `json:"rule_id"`
+	Metric        string     `json:"metric"`
+	Value         float64    `json:"value"`
+	Min           *float64   `json:"min"`
+	Max           *float64   `json:"max"`
+	Summary       string     `json:"summary"`
+	Channel       string     `json:"channel"`
+	Target        string     `json:"target"`
+	FiredAt       time.Time  `json:"fired_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	DeliveryError *string    `json:"delivery_error"`
+}
+
+// AlertNotificationView is the body posted to webhook alert channels.
+type AlertNotificationView struct {
+	AgencyID   string    `json:"agency_id"`
+	AgencyName string    `json:"agency_name"`
+	Alert      AlertView `json:"alert"`
+}
+
+// Webhook models
+
+type WebhookView struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreatedWebhookView carries the signing secret, which is only shown once.
+type CreatedWebhookView struct {
+	WebhookView
+	Secret string `json:"secret"`
+}
+
+// WebhookEventView is the body of every webhook delivery. Data is the
+// record the event is about.
+type WebhookEventView struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	AgencyID   string      `json:"agency_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookDeliveryView is one entry in a webhook's delivery log.
+// NextAttemptAt is set while a pending delivery waits for a retry.
+type WebhookDeliveryView struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ScoreStatusChangeView is the data of a score.status_changed event.
+type ScoreStatusChangeView struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Score       int    `json:"score"`
+	PrimaryRisk string `json:"primary_risk"`
 }
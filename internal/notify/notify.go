@@ -0,0 +1,293 @@
+// Package notify delivers messages to people and systems outside the server.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Message is what a channel delivers. Email sends Subject and Body; the
+// webhook posts Payload as JSON.
+type Message struct {
+	Subject string
+	Body    string
+	Payload interface{}
+}
+
+// Channel is a way of delivering messages. Targets are channel specific: an
+// email address or a URL.
+type Channel interface {
+	// Validate reports whether target is something the channel can deliver to.
+	Validate(target string) error
+	Send(target string, msg Message) error
+}
+
+// SMTP sends plain text email through a relay. Username may be empty for
+// relays that don't authenticate. Timeout bounds a whole send, from dialling
+// the relay to its reply to QUIT; zero means defaultSMTPTimeout.
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+const defaultSMTPTimeout = 30 * time.Second
+
+func (s *SMTP) Validate(target string) error {
+	if _, err := mail.ParseAddress(target); err != nil {
+		return fmt.Errorf("invalid email address %q", target)
+	}
+	return nil
+}
+
+func (s *SMTP) Send(target string, msg Message) error {
+	to, err := mail.ParseAddress(target)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", s.From)
+	fmt.Fprintf(&body, "To: %s\r\n", to.Address)
+	fmt.Fprintf(&body, "Subject: %s\r\n", strings.NewReplacer("\r", "", "\n", " ").Replace(msg.Subject))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	body.WriteString(msg.Body)
+
+	// smtp.SendMail has no timeouts, so a stalled relay would block the
+	// caller forever; this is the same exchange with a deadline on the conn
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = defaultSMTPTimeout
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(s.Host, s.Port), timeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Webhook posts the message payload as JSON and treats any 2xx response as
+// delivered. Targets are chosen by agency users, so it only connects to
+// public addresses and doesn't follow redirects; otherwise a webhook could be
+// pointed at the server's own network or a cloud metadata endpoint.
+type Webhook struct {
+	client *http.Client
+}
+
+func NewWebhook(timeout time.Duration) *Webhook {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Checked against the address actually dialled, so a hostname that
+		// resolves differently after validation is still caught
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Webhook{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Validate accepts http and https URLs whose host resolves only to public
+// addresses.
+func (w *Webhook) Validate(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid webhook URL %q", target)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("webhook host %q could not be resolved", u.Hostname())
+	}
+	for _, a := range addrs {
+		if blockedIP(a.IP) {
+			return fmt.Errorf("webhook host %q is not a public address", u.Hostname())
+		}
+	}
+	return nil
+}
+
+// specialRanges are reserved blocks not covered by the net.IP predicates
+// used in blockedIP.
+var specialRanges = mustParseCIDRs(
+	"0.0.0.0/8",       // "this" network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved, including broadcast
+	"64:ff9b::/96",    // NAT64, which embeds IPv4 addresses
+	"2001:db8::/32",   // documentation
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// blockedIP reports whether ip is loopback, private, link-local (which
+// includes the 169.254.169.254 metadata endpoint), multicast, unspecified or
+// otherwise reserved.
+func blockedIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range specialRanges {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) Send(target string, msg Message) error {
+	body, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return err
+	}
+	_, err = w.Post(target, body, nil)
+	return err
+}
+
+// Post sends a JSON body with any extra headers and returns the response
+// status, or 0 when there was no response. Non-2xx responses, including
+// redirects, are errors.
+func (w *Webhook) Post(target string, body []byte, header http.Header) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// SigningSecretPrefix marks webhook signing secrets.
+const SigningSecretPrefix = "whsec_"
+
+// GenerateSigningSecret returns a new random webhook signing secret.
+func GenerateSigningSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return SigningSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign is the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+// Receivers recompute it to check a delivery is genuine, and reject stale
+// timestamps to stop replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		target string
+		ok     bool
+	}{
+		{"https://93.184.216.34/hooks", true},
+		{"http://[2606:4700::1111]/hooks", true},
+		{"ftp://93.184.216.34/hooks", false},
+		{"https:///hooks", false},
+		{"not a url", false},
+		{"http://127.0.0.1/hooks", false},
+		{"http://127.0.0.1:8080/hooks", false},
+		{"http://localhost/hooks", false},
+		{"http://10.0.0.5/hooks", false},
+		{"http://172.16.0.1/hooks", false},
+		{"http://192.168.1.1/hooks", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://100.64.0.1/hooks", false},
+		{"http://0.0.0.0/hooks", false},
+		{"http://255.255.255.255/hooks", false},
+		{"http://224.0.0.1/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://[::]/hooks", false},
+		{"http://[fe80::1]/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+		{"http://[::ffff:127.0.0.1]/hooks", false},
+		{"http://[::ffff:169.254.169.254]/hooks", false},
+		{"http://[64:ff9b::a9fe:a9fe]/hooks", false},
+	}
+
+	w := NewWebhook(time.Second)
+	for _, tt := range tests {
+		err := w.Validate(tt.target)
+		if tt.ok && err != nil {
+			t.Errorf("Validate(%q) = %v, want ok", tt.target, err)
+		} else if !tt.ok && err == nil {
+			t.Errorf("Validate(%q) accepted a blocked target", tt.target)
+		}
+	}
+}
+
+func TestWebhookPostRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Skips Validate, as a target that resolved publicly when saved might not
+	// by the time it's sent
+	status, err := NewWebhook(time.Second).Post(server.URL, []byte(`{}`), nil)
+	if err == nil {
+		t.Fatal("Post to a loopback server succeeded")
+	}
+	if status != 0 || called {
+		t.Errorf("request reached the server (status %d)", status)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac whsec_test
+	got := Sign("whsec_test", 1700000000, []byte(`{"id":1}`))
+	want := "2f441ba4b3b2d50d28a9ab9d9fd8880376ecd1eb5d0435401553f5d8d0a5dcf8"
+	if got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestSMTPSendTimesOutOnAStalledRelay(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// Accept the connection but never send the greeting
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	s := &SMTP{Host: host, Port: port, From: "alerts@example.com", Timeout: 200 * time.Millisecond}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Send("founder@example.com", Message{Subject: "test"}) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Send to a stalled relay succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send did not time out")
+	}
+}
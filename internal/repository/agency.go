@@ -13,12 +13,16 @@ type AgencyEntity struct {
 	OwnerUserID  string
 	Name         string
 	BaseCurrency string
+	Role         string
 	CreatedAt    time.Time
 }
 
 type AgencyRepository interface {
-	Create(userID string, name string, currency string, startingCash float64) error
-	GetByUserID(userID string) (*AgencyEntity, error)
+	Create(userID string, name string, currency string, startingCash float64, openingDate string) (*AgencyEntity, error)
+	ListByUserID(userID string) ([]AgencyEntity, error)
+	GetForUser(userID string, agencyID string) (*AgencyEntity, error)
+	GetByID(agencyID string) (*AgencyEntity, error)
+	ListIDs() ([]string, error)
 }
 
 type postgresAgencyRepository struct {
@@ -29,47 +33,132 @@ func NewAgencyRepository(db *sql.DB) AgencyRepository {
 	return &postgresAgencyRepository{db: db}
 }
 
-func (r *postgresAgencyRepository) Create(userID string, name string, currency string, startingCash float64) error {
+func (r *postgresAgencyRepository) Create(userID string, name string, currency string, startingCash float64, openingDate string) (*AgencyEntity, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
 	agencyID := uuid.New().String()
+	createdAt := time.Now()
 	_, err = tx.Exec(`
 		INSERT INTO agencies (id, owner_user_id, name, base_currency, created_at)
 		VALUES ($1, $2, $3, $4, $5)
-	`, agencyID, userID, name, currency, time.Now())
+	`, agencyID, userID, name, currency, createdAt)
+
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to insert agency: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO agency_members (agency_id, founder_id, role)
+		VALUES ($1, $2, 'owner')
+	`, agencyID, userID)
 
 	if err != nil {
-		return fmt.Errorf("failed to insert agency: %v", err)
+		return nil, fmt.Errorf("failed to insert agency owner: %v", err)
 	}
 
+	// Seed the opening balance where survival and reality-score math reads cash
 	snapshotID := uuid.New().String()
 	_, err = tx.Exec(`
-		INSERT INTO cash_snapshots (id, agency_id, date, cash_balance, created_at)
+		INSERT INTO daily_cash_snapshots (id, agency_id, date, cash_balance, created_at)
 		VALUES ($1, $2, $3, $4, $5)
-	`, snapshotID, agencyID, time.Now(), startingCash, time.Now())
+	`, snapshotID, agencyID, openingDate, startingCash, time.Now())
 
 	if err != nil {
-		return fmt.Errorf("failed to insert cash snapshot: %v", err)
+		return nil, fmt.Errorf("failed to insert cash snapshot: %v", err)
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &AgencyEntity{
+		ID:           agencyID,
+		OwnerUserID:  userID,
+		Name:         name,
+		BaseCurrency: currency,
+		Role:         "owner",
+		CreatedAt:    createdAt,
+	}, nil
 }
 
-func (r *postgresAgencyRepository) GetByUserID(userID string) (*AgencyEntity, error) {
-	row := r.db.QueryRow(`
-		SELECT id, name, base_currency, created_at 
-		FROM agencies 
-		WHERE owner_user_id = $1
+func (r *postgresAgencyRepository) ListByUserID(userID string) ([]AgencyEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT a.id, a.owner_user_id, a.name, a.base_currency, m.role, a.created_at
+		FROM agencies a
+		JOIN agency_members m ON m.agency_id = a.id
+		WHERE m.founder_id = $1
+		ORDER BY a.created_at
 	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agencies []AgencyEntity
+	for rows.Next() {
+		var a AgencyEntity
+		if err := rows.Scan(&a.ID, &a.OwnerUserID, &a.Name, &a.BaseCurrency, &a.Role, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		agencies = append(agencies, a)
+	}
+	return agencies, rows.Err()
+}
+
+// GetForUser returns the agency only if userID is a member of it.
+func (r *postgresAgencyRepository) GetForUser(userID string, agencyID string) (*AgencyEntity, error) {
+	row := r.db.QueryRow(`
+		SELECT a.id, a.owner_user_id, a.name, a.base_currency, m.role, a.created_at
+		FROM agencies a
+		JOIN agency_members m ON m.agency_id = a.id
+		WHERE m.founder_id = $1 AND a.id = $2
+	`, userID, agencyID)
 
 	var a AgencyEntity
-	err := row.Scan(&a.ID, &a.Name, &a.BaseCurrency, &a.CreatedAt)
+	err := row.Scan(&a.ID, &a.OwnerUserID, &a.Name, &a.BaseCurrency, &a.Role, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresAgencyRepository) GetByID(agencyID string) (*AgencyEntity, error) {
+	row := r.db.QueryRow(`
+		SELECT id, owner_user_id, name, base_currency, created_at
+		FROM agencies
+		WHERE id = $1
+	`, agencyID)
+
+	var a AgencyEntity
+	err := row.Scan(&a.ID, &a.OwnerUserID, &a.Name, &a.BaseCurrency, &a.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return &a, nil
 }
+
+// ListIDs returns every agency, for background jobs that work across them.
+func (r *postgresAgencyRepository) ListIDs() ([]string, error) {
+	rows, err := r.db.Query(`SELECT id FROM agencies ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertEntity is one alert fired by a rule, with the rule's bounds and
+// destination as they were at the time. RuleID is nil once the rule is
+// deleted.
+type AlertEntity struct {
+	ID            string
+	AgencyID      string
+	RuleID        *string
+	Metric        string
+	Value         float64
+	Min           *float64
+	Max           *float64
+	Channel       string
+	Target        string
+	FiredAt       time.Time
+	DeliveredAt   *time.Time
+	DeliveryError *string
+}
+
+type AlertRepository interface {
+	// Create records the alert with its first delivery attempt under way;
+	// it can't be claimed for a retry until retryAt.
+	Create(alert AlertEntity, retryAt time.Time) (*AlertEntity, error)
+	// LatestUndelivered returns the rule's most recent alert fired at or
+	// after since, or nil when there is none or it has been delivered.
+	LatestUndelivered(ruleID string, since time.Time) (*AlertEntity, error)
+	// ClaimRetry takes an undelivered alert whose retry is due by now and
+	// holds it until retryAt. It reports whether the caller won the claim and
+	// should send the alert.
+	ClaimRetry(id string, now time.Time, retryAt time.Time) (bool, error)
+	// RecordDelivery stores the outcome of sending the alert: deliveryErr is
+	// nil when it was delivered.
+	RecordDelivery(id string, deliveredAt time.Time, deliveryErr *string) error
+	// List returns the agency's most recent alerts, newest first.
+	List(agencyID string, limit int) ([]AlertEntity, error)
+}
+
+type postgresAlertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) AlertRepository {
+	return &postgresAlertRepository{db: db}
+}
+
+func (r *postgresAlertRepository) Create(a AlertEntity, retryAt time.Time) (*AlertEntity, error) {
+	a.ID = uuid.New().String()
+	_, err := r.db.Exec(`
+		INSERT INTO alerts (id, agency_id, rule_id, metric, value, min_value, max_value, channel, target, fired_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, a.ID, a.AgencyID, a.RuleID, a.Metric, a.Value, a.Min, a.Max, a.Channel, a.Target, a.FiredAt, retryAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresAlertRepository) LatestUndelivered(ruleID string, since time.Time) (*AlertEntity, error) {
+	a := AlertEntity{RuleID: &ruleID}
+	err := r.db.QueryRow(`
+		SELECT id, agency_id, metric, value, min_value, max_value, channel, target, fired_at, delivery_error
+		FROM alerts
+		WHERE id = (SELECT id FROM alerts WHERE rule_id = $1 AND fired_at >= $2 ORDER BY fired_at DESC LIMIT 1)
+			AND delivered_at IS NULL
+	`, ruleID, since).Scan(&a.ID, &a.AgencyID, &a.Metric, &a.Value, &a.Min, &a.Max, &a.Channel, &a.Target, &a.FiredAt, &a.DeliveryError)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresAlertRepository) ClaimRetry(id string, now time.Time, retryAt time.Time) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE alerts SET next_attempt_at = $3
+		WHERE id = $1 AND delivered_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= $2)
+	`, id, now, retryAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresAlertRepository) RecordDelivery(id string, deliveredAt time.Time, deliveryErr *string) error {
+	var err error
+	if deliveryErr == nil {
+		_, err = r.db.Exec(`UPDATE alerts SET delivered_at = $2, delivery_error = NULL WHERE id = $1`, id, deliveredAt)
+	} else {
+		_, err = r.db.Exec(`UPDATE alerts SET delivery_error = $2 WHERE id = $1`, id, *deliveryErr)
+	}
+	return err
+}
+
+func (r *postgresAlertRepository) List(agencyID string, limit int) ([]AlertEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, rule_id, metric, value, min_value, max_value, channel, target, fired_at, delivered_at, delivery_error
+		FROM alerts
+		WHERE agency_id = $1
+		ORDER BY fired_at DESC
+		LIMIT $2
+	`, agencyID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []AlertEntity
+	for rows.Next() {
+		a := AlertEntity{AgencyID: agencyID}
+		var delivered sql.NullTime
+		if err := rows.Scan(&a.ID, &a.RuleID, &a.Metric, &a.Value, &a.Min, &a.Max, &a.Channel, &a.Target,
+			&a.FiredAt, &delivered, &a.DeliveryError); err != nil {
+			return nil, err
+		}
+		if delivered.Valid {
+			a.DeliveredAt = &delivered.Time
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertRuleEntity alerts through Channel to Target when Metric falls below
+// Min or rises above Max. BreachedSince is set while an alerted breach lasts.
+type AlertRuleEntity struct {
+	ID              string
+	AgencyID        string
+	Metric          string
+	Min             *float64
+	Max             *float64
+	Channel         string
+	Target          string
+	CooldownMinutes int
+	Enabled         bool
+	BreachedSince   *time.Time
+	LastFiredAt     *time.Time
+	CreatedAt       time.Time
+}
+
+type AlertRuleRepository interface {
+	Create(rule AlertRuleEntity) (*AlertRuleEntity, error)
+	List(agencyID string, enabledOnly bool) ([]AlertRuleEntity, error)
+	Get(agencyID string, id string) (*AlertRuleEntity, error)
+	// Update saves the rule's settings and clears any breach so it is judged
+	// afresh against them.
+	Update(rule AlertRuleEntity) error
+	Delete(agencyID string, id string) (bool, error)
+	// ClaimFiring marks the rule breached and fired at now, unless it is
+	// already breached or fired within its cooldown. It reports whether the
+	// caller won the claim and should send the alert.
+	ClaimFiring(id string, now time.Time) (bool, error)
+	// ReleaseClaim undoes a claim made at claimedAt, restoring the rule's
+	// previous last fired time, so the alert is tried again on the next
+	// evaluation. Later claims are left alone.
+	ReleaseClaim(id string, claimedAt time.Time, previousFiredAt *time.Time) error
+	ClearBreach(id string) error
+}
+
+type postgresAlertRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRuleRepository(db *sql.DB) AlertRuleRepository {
+	return &postgresAlertRuleRepository{db: db}
+}
+
+func (r *postgresAlertRuleRepository) Create(rule AlertRuleEntity) (*AlertRuleEntity, error) {
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO alert_rules (id, agency_id, metric, min_value, max_value, channel, target, cooldown_minutes, enabled, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, rule.ID, rule.AgencyID, rule.Metric, rule.Min, rule.Max, rule.Channel, rule.Target, rule.CooldownMinutes, rule.Enabled, rule.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *postgresAlertRuleRepository) List(agencyID string, enabledOnly bool) ([]AlertRuleEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, metric, min_value, max_value, channel, target, cooldown_minutes, enabled, breached_since, last_fired_at, created_at
+		FROM alert_rules
+		WHERE agency_id = $1 AND (NOT $2 OR enabled)
+		ORDER BY created_at
+	`, agencyID, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []AlertRuleEntity
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rule.AgencyID = agencyID
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *postgresAlertRuleRepository) Get(agencyID string, id string) (*AlertRuleEntity, error) {
+	rule, err := scanAlertRule(r.db.QueryRow(`
+		SELECT id, metric, min_value, max_value, channel, target, cooldown_minutes, enabled, breached_since, last_fired_at, created_at
+		FROM alert_rules
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	rule.AgencyID = agencyID
+	return rule, nil
+}
+
+func (r *postgresAlertRuleRepository) Update(rule AlertRuleEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE alert_rules
+		SET metric = $3, min_value = $4, max_value = $5, channel = $6, target = $7,
+			cooldown_minutes = $8, enabled = $9, breached_since = NULL
+		WHERE agency_id = $1 AND id = $2
+	`, rule.AgencyID, rule.ID, rule.Metric, rule.Min, rule.Max, rule.Channel, rule.Target, rule.CooldownMinutes, rule.Enabled)
+	return err
+}
+
+func (r *postgresAlertRuleRepository) Delete(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM alert_rules WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresAlertRuleRepository) ClaimFiring(id string, now time.Time) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE alert_rules SET breached_since = $2, last_fired_at = $2
+		WHERE id = $1 AND breached_since IS NULL
+			AND (last_fired_at IS NULL OR last_fired_at <= $2 - cooldown_minutes * INTERVAL '1 minute')
+	`, id, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresAlertRuleRepository) ReleaseClaim(id string, claimedAt time.Time, previousFiredAt *time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE alert_rules SET breached_since = NULL, last_fired_at = $3
+		WHERE id = $1 AND last_fired_at = $2
+	`, id, claimedAt, previousFiredAt)
+	return err
+}
+
+func (r *postgresAlertRuleRepository) ClearBreach(id string) error {
+	_, err := r.db.Exec(`UPDATE alert_rules SET breached_since = NULL WHERE id = $1`, id)
+	return err
+}
+
+func scanAlertRule(row rowScanner) (*AlertRuleEntity, error) {
+	var rule AlertRuleEntity
+	var breached, fired sql.NullTime
+	if err := row.Scan(&rule.ID, &rule.Metric, &rule.Min, &rule.Max, &rule.Channel, &rule.Target,
+		&rule.CooldownMinutes, &rule.Enabled, &breached, &fired, &rule.CreatedAt); err != nil {
+		return nil, err
+	}
+	if breached.Valid {
+		rule.BreachedSince = &breached.Time
+	}
+	if fired.Valid {
+		rule.LastFiredAt = &fired.Time
+	}
+	return &rule, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APITokenEntity struct {
+	ID           string
+	FounderID    string
+	FounderEmail string
+	Name         string
+	Prefix       string
+	Scope        string
+	ExpiresAt    *time.Time
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+}
+
+type APITokenRepository interface {
+	Create(founderID string, name string, prefix string, tokenHash string, scope string, expiresAt *time.Time) (*APITokenEntity, error)
+	ListActive(founderID string) ([]APITokenEntity, error)
+	Revoke(founderID string, id string) (bool, error)
+	GetActiveByHash(tokenHash string) (*APITokenEntity, error)
+	TouchLastUsed(id string) error
+}
+
+type postgresAPITokenRepository struct {
+	db *sql.DB
+}
+
+func NewAPITokenRepository(db *sql.DB) APITokenRepository {
+	return &postgresAPITokenRepository{db: db}
+}
+
+func (r *postgresAPITokenRepository) Create(founderID string, name string, prefix string, tokenHash string, scope string, expiresAt *time.Time) (*APITokenEntity, error) {
+	id := uuid.New().String()
+	now := time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO api_tokens (id, founder_id, name, token_prefix, token_hash, scope, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, id, founderID, name, prefix, tokenHash, scope, expiresAt, now)
+	if err != nil {
+		return nil, err
+	}
+	return &APITokenEntity{
+		ID:        id,
+		FounderID: founderID,
+		Name:      name,
+		Prefix:    prefix,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}, nil
+}
+
+func (r *postgresAPITokenRepository) ListActive(founderID string) ([]APITokenEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, token_prefix, scope, expires_at, last_used_at, created_at
+		FROM api_tokens
+		WHERE founder_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, founderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []APITokenEntity
+	for rows.Next() {
+		t := APITokenEntity{FounderID: founderID}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Prefix, &t.Scope, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *postgresAPITokenRepository) Revoke(founderID string, id string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE api_tokens SET revoked_at = $3
+		WHERE id = $1 AND founder_id = $2 AND revoked_at IS NULL
+	`, id, founderID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresAPITokenRepository) GetActiveByHash(tokenHash string) (*APITokenEntity, error) {
+	var t APITokenEntity
+	err := r.db.QueryRow(`
+		SELECT t.id, t.founder_id, f.email, t.name, t.token_prefix, t.scope, t.expires_at, t.last_used_at, t.created_at
+		FROM api_tokens t
+		JOIN founders f ON f.id = t.founder_id
+		WHERE t.token_hash = $1
+		  AND t.revoked_at IS NULL
+		  AND (t.expires_at IS NULL OR t.expires_at > $2)
+	`, tokenHash, time.Now()).Scan(&t.ID, &t.FounderID, &t.FounderEmail, &t.Name, &t.Prefix, &t.Scope, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *postgresAPITokenRepository) TouchLastUsed(id string) error {
+	_, err := r.db.Exec(`UPDATE api_tokens SET last_used_at = $2 WHERE id = $1`, id, time.Now())
+	return err
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BankAccountEntity struct {
+	ID         string
+	AgencyID   string
+	Name       string
+	Currency   string
+	Restricted bool
+	CreatedAt  time.Time
+}
+
+// AccountBalanceEntity is an account's most recent snapshot. BaseBalance is
+// the balance converted to the agency's base currency.
+type AccountBalanceEntity struct {
+	AccountID    string
+	Name         string
+	Currency     string
+	Restricted   bool
+	Date         string
+	Balance      float64
+	ExchangeRate float64
+	BaseBalance  float64
+}
+
+type BankAccountRepository interface {
+	Create(agencyID string, name string, currency string, restricted bool) (*BankAccountEntity, error)
+	List(agencyID string) ([]BankAccountEntity, error)
+	Get(agencyID string, id string) (*BankAccountEntity, error)
+	Update(account BankAccountEntity) error
+	Archive(agencyID string, id string) (bool, error)
+	UpsertSnapshot(agencyID string, accountID string, date string, balance float64, exchangeRate float64) error
+	LatestBalances(agencyID string, onOrBefore string) ([]AccountBalanceEntity, error)
+}
+
+type postgresBankAccountRepository struct {
+	db *sql.DB
+}
+
+func NewBankAccountRepository(db *sql.DB) BankAccountRepository {
+	return &postgresBankAccountRepository{db: db}
+}
+
+func (r *postgresBankAccountRepository) Create(agencyID string, name string, currency string, restricted bool) (*BankAccountEntity, error) {
+	id := uuid.New().String()
+	now := time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO bank_accounts (id, agency_id, name, currency, restricted, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, id, agencyID, name, currency, restricted, now)
+	if err != nil {
+		return nil, err
+	}
+	return &BankAccountEntity{
+		ID:         id,
+		AgencyID:   agencyID,
+		Name:       name,
+		Currency:   currency,
+		Restricted: restricted,
+		CreatedAt:  now,
+	}, nil
+}
+
+func (r *postgresBankAccountRepository) List(agencyID string) ([]BankAccountEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, currency, restricted, created_at FROM bank_accounts
+		WHERE agency_id = $1 AND archived_at IS NULL
+		ORDER BY created_at
+	`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []BankAccountEntity
+	for rows.Next() {
+		a := BankAccountEntity{AgencyID: agencyID}
+		if err := rows.Scan(&a.ID, &a.Name, &a.Currency, &a.Restricted, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *postgresBankAccountRepository) Get(agencyID string, id string) (*BankAccountEntity, error) {
+	a := BankAccountEntity{AgencyID: agencyID}
+	err := r.db.QueryRow(`
+		SELECT id, name, currency, restricted, created_at FROM bank_accounts
+		WHERE agency_id = $1 AND id = $2 AND archived_at IS NULL
+	`, agencyID, id).Scan(&a.ID, &a.Name, &a.Currency, &a.Restricted, &a.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *postgresBankAccountRepository) Update(a BankAccountEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE bank_accounts SET name = $3, restricted = $4
+		WHERE agency_id = $1 AND id = $2
+	`, a.AgencyID, a.ID, a.Name, a.Restricted)
+	return err
+}
+
+func (r *postgresBankAccountRepository) Archive(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE bank_accounts SET archived_at = $3
+		WHERE agency_id = $1 AND id = $2 AND archived_at IS NULL
+	`, agencyID, id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresBankAccountRepository) UpsertSnapshot(agencyID string, accountID string, date string, balance float64, exchangeRate float64) error {
+	_, err := r.db.Exec(`
+		INSERT INTO bank_account_snapshots (id, account_id, agency_id, date, balance, exchange_rate)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, date) DO UPDATE SET
+			balance = EXCLUDED.balance,
+			exchange_rate = EXCLUDED.exchange_rate
+	`, uuid.New().String(), accountID, agencyID, date, balance, exchangeRate)
+	return err
+}
+
+// LatestBalances returns, for every active account with at least one snapshot,
+// the most recent snapshot on or before the given date.
+func (r *postgresBankAccountRepository) LatestBalances(agencyID string, onOrBefore string) ([]AccountBalanceEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT ON (a.id) a.id, a.name, a.currency, a.restricted, s.date, s.balance, s.exchange_rate
+		FROM bank_accounts a
+		JOIN bank_account_snapshots s ON s.account_id = a.id
+		WHERE a.agency_id = $1 AND a.archived_at IS NULL AND s.date <= $2
+		ORDER BY a.id, s.date DESC
+	`, agencyID, onOrBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []AccountBalanceEntity
+	for rows.Next() {
+		var b AccountBalanceEntity
+		var date time.Time
+		if err := rows.Scan(&b.AccountID, &b.Name, &b.Currency, &b.Restricted, &date, &b.Balance, &b.ExchangeRate); err != nil {
+			return nil, err
+		}
+		b.Date = date.Format("2006-01-02")
+		b.BaseBalance = b.Balance * b.ExchangeRate
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
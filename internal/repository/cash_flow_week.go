@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CashFlowWeekEntity pairs the forecast for a week with what actually
+// happened. Either side may be missing.
+type CashFlowWeekEntity struct {
+	AgencyID         string
+	WeekStart        string
+	ForecastInflows  *float64
+	ForecastOutflows *float64
+	ForecastClosing  *float64
+	ActualInflows    *float64
+	ActualOutflows   *float64
+	ActualClosing    *float64
+}
+
+type CashFlowWeekRepository interface {
+	// FreezeForecast stores the forecast for a week that hasn't started. A
+	// week's forecast is written once; it reports false if one was already
+	// stored.
+	FreezeForecast(week CashFlowWeekEntity) (bool, error)
+	RecordActual(agencyID string, weekStart string, inflows float64, outflows float64, closing float64, recordedBy string) error
+	ListWithActuals(agencyID string, from string, to string) ([]CashFlowWeekEntity, error)
+}
+
+type postgresCashFlowWeekRepository struct {
+	db *sql.DB
+}
+
+func NewCashFlowWeekRepository(db *sql.DB) CashFlowWeekRepository {
+	return &postgresCashFlowWeekRepository{db: db}
+}
+
+func (r *postgresCashFlowWeekRepository) FreezeForecast(w CashFlowWeekEntity) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO cash_flow_weeks (agency_id, week_start, forecast_inflows, forecast_outflows, forecast_closing, forecast_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (agency_id, week_start) DO NOTHING
+	`, w.AgencyID, w.WeekStart, w.ForecastInflows, w.ForecastOutflows, w.ForecastClosing, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresCashFlowWeekRepository) RecordActual(agencyID string, weekStart string, inflows float64, outflows float64, closing float64, recordedBy string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO cash_flow_weeks (agency_id, week_start, actual_inflows, actual_outflows, actual_closing, actuals_recorded_by, actuals_recorded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (agency_id, week_start) DO UPDATE SET
+			actual_inflows = EXCLUDED.actual_inflows,
+			actual_outflows = EXCLUDED.actual_outflows,
+			actual_closing = EXCLUDED.actual_closing,
+			actuals_recorded_by = EXCLUDED.actuals_recorded_by,
+			actuals_recorded_at = EXCLUDED.actuals_recorded_at
+	`, agencyID, weekStart, inflows, outflows, closing, recordedBy, time.Now())
+	return err
+}
+
+func (r *postgresCashFlowWeekRepository) ListWithActuals(agencyID string, from string, to string) ([]CashFlowWeekEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT week_start, forecast_inflows, forecast_outflows, forecast_closing, actual_inflows, actual_outflows, actual_closing
+		FROM cash_flow_weeks
+		WHERE agency_id = $1 AND week_start >= $2 AND week_start <= $3 AND actuals_recorded_at IS NOT NULL
+		ORDER BY week_start
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeks []CashFlowWeekEntity
+	for rows.Next() {
+		w := CashFlowWeekEntity{AgencyID: agencyID}
+		var start time.Time
+		if err := rows.Scan(&start, &w.ForecastInflows, &w.ForecastOutflows, &w.ForecastClosing, &w.ActualInflows, &w.ActualOutflows, &w.ActualClosing); err != nil {
+			return nil, err
+		}
+		w.WeekStart = start.Format("2006-01-02")
+		weeks = append(weeks, w)
+	}
+	return weeks, rows.Err()
+}
@@ -12,9 +12,18 @@ type CashSnapshotEntity struct {
 	CashBalance float64
 }
 
+type CashSnapshotRevisionEntity struct {
+	PreviousBalance float64
+	NewBalance      float64
+	ChangedBy       *string
+	ChangedAt       time.Time
+}
+
 type CashSnapshotRepository interface {
-	CreateDaily(agencyID string, cashBalance float64) error
-	GetToday(agencyID string) (*CashSnapshotEntity, error)
+	Upsert(agencyID string, date string, cashBalance float64, changedBy string) error
+	ListRevisions(agencyID string, date string) ([]CashSnapshotRevisionEntity, error)
+	GetByDate(agencyID string, date string) (*CashSnapshotEntity, error)
+	ListInRange(agencyID string, from string, to string) ([]CashSnapshotEntity, error)
 	GetLatestBefore(agencyID string, date string) (*float64, error)
 	GetLatest(agencyID string) (*float64, error)
 }
@@ -27,33 +36,96 @@ func NewCashSnapshotRepository(db *sql.DB) CashSnapshotRepository {
 	return &postgresCashSnapshotRepository{db: db}
 }
 
-func (r *postgresCashSnapshotRepository) CreateDaily(agencyID string, cashBalance float64) error {
-	id := uuid.New().String()
-	date := time.Now().Format("2006-01-02")
+// Upsert records the balance for date. Overwriting an existing snapshot keeps
+// the prior value in cash_snapshot_revisions.
+func (r *postgresCashSnapshotRepository) Upsert(agencyID string, date string, cashBalance float64, changedBy string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.Exec(`
+	res, err := tx.Exec(`
 		INSERT INTO daily_cash_snapshots (id, agency_id, date, cash_balance)
 		VALUES ($1, $2, $3, $4)
-	`, id, agencyID, date, cashBalance)
+		ON CONFLICT (agency_id, date) DO NOTHING
+	`, uuid.New().String(), agencyID, date, cashBalance)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n > 0 {
+		return tx.Commit()
+	}
+
+	var snapshotID string
+	var previous float64
+	err = tx.QueryRow(`
+		SELECT id, cash_balance FROM daily_cash_snapshots
+		WHERE agency_id = $1 AND date = $2
+		FOR UPDATE
+	`, agencyID, date).Scan(&snapshotID, &previous)
+	if err != nil {
+		return err
+	}
+	if previous == cashBalance {
+		return tx.Commit()
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO cash_snapshot_revisions (id, snapshot_id, agency_id, date, previous_balance, new_balance, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, uuid.New().String(), snapshotID, agencyID, date, previous, cashBalance, changedBy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE daily_cash_snapshots SET cash_balance = $2 WHERE id = $1`, snapshotID, cashBalance)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
 
-func (r *postgresCashSnapshotRepository) GetToday(agencyID string) (*CashSnapshotEntity, error) {
-	today := time.Now().Format("2006-01-02")
+func (r *postgresCashSnapshotRepository) ListRevisions(agencyID string, date string) ([]CashSnapshotRevisionEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT previous_balance, new_balance, changed_by, changed_at
+		FROM cash_snapshot_revisions
+		WHERE agency_id = $1 AND date = $2
+		ORDER BY changed_at
+	`, agencyID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []CashSnapshotRevisionEntity
+	for rows.Next() {
+		var rev CashSnapshotRevisionEntity
+		if err := rows.Scan(&rev.PreviousBalance, &rev.NewBalance, &rev.ChangedBy, &rev.ChangedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *postgresCashSnapshotRepository) GetByDate(agencyID string, date string) (*CashSnapshotEntity, error) {
 	var snap CashSnapshotEntity
 	err := r.db.QueryRow(`
 		SELECT cash_balance 
 		FROM daily_cash_snapshots 
 		WHERE agency_id = $1 AND date = $2
-	`, agencyID, today).Scan(&snap.CashBalance)
+	`, agencyID, date).Scan(&snap.CashBalance)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
 	}
-	snap.Date = today
+	snap.Date = date
 	return &snap, nil
 }
 
@@ -91,3 +163,27 @@ func (r *postgresCashSnapshotRepository) GetLatest(agencyID string) (*float64, e
 	}
 	return &balance, nil
 }
+
+func (r *postgresCashSnapshotRepository) ListInRange(agencyID string, from string, to string) ([]CashSnapshotEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT date, cash_balance FROM daily_cash_snapshots
+		WHERE agency_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snaps []CashSnapshotEntity
+	for rows.Next() {
+		var snap CashSnapshotEntity
+		var date time.Time
+		if err := rows.Scan(&date, &snap.CashBalance); err != nil {
+			return nil, err
+		}
+		snap.Date = date.Format("2006-01-02")
+		snaps = append(snaps, snap)
+	}
+	return snaps, rows.Err()
+}
@@ -16,6 +16,7 @@ type ClientEntity struct {
 type ClientRepository interface {
 	Create(agencyID string, name string) (*ClientEntity, error)
 	GetAllActive(agencyID string) ([]ClientEntity, error)
+	Get(agencyID string, id string) (*ClientEntity, error)
 }
 
 type postgresClientRepository struct {
@@ -60,3 +61,17 @@ func (r *postgresClientRepository) GetAllActive(agencyID string) ([]ClientEntity
 	}
 	return clients, nil
 }
+
+func (r *postgresClientRepository) Get(agencyID string, id string) (*ClientEntity, error) {
+	c := ClientEntity{AgencyID: agencyID}
+	err := r.db.QueryRow(`
+		SELECT id, name, status FROM clients
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id).Scan(&c.ID, &c.Name, &c.Status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
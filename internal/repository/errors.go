@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicate is returned when an insert violates a unique constraint.
+var ErrDuplicate = errors.New("duplicate record")
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -7,15 +7,58 @@ import (
 	"github.com/google/uuid"
 )
 
+type RevenueEntity struct {
+	ID        string
+	AgencyID  string
+	Date      string
+	Amount    float64
+	Source    string
+	CreatedAt time.Time
+}
+
+type CostEntity struct {
+	ID       string
+	AgencyID string
+	Date     string
+	Amount   float64
+	Type     string
+	Label    string
+	Category string
+	// RecurringCostID is set on costs posted from a recurring template.
+	RecurringCostID *string
+	CreatedAt       time.Time
+}
+
+// MonthlyTotalEntity is a calendar month's ledger totals; Month is YYYY-MM.
+// HasEntries tells a month with no revenue or cost rows from one whose
+// entries net to zero.
+type MonthlyTotalEntity struct {
+	Month      string
+	Revenue    float64
+	Costs      float64
+	HasEntries bool
+}
+
 type FinanceRepository interface {
-	AddRevenue(agencyID string, amount float64, source string) error
-	AddCost(agencyID string, amount float64, costType string, label string, category string) error
+	AddRevenue(agencyID string, date string, amount float64, source string) (string, error)
+	AddCost(agencyID string, date string, amount float64, costType string, label string, category string) (string, error)
+	ListRevenues(agencyID string, from string, to string) ([]RevenueEntity, error)
+	ListCosts(agencyID string, from string, to string) ([]CostEntity, error)
+	GetRevenue(agencyID string, id string) (*RevenueEntity, error)
+	GetCost(agencyID string, id string) (*CostEntity, error)
+	UpdateRevenue(revenue RevenueEntity) error
+	UpdateCost(cost CostEntity) error
+	DeleteRevenue(agencyID string, id string) (bool, error)
+	DeleteCost(agencyID string, id string) (bool, error)
 	SumRevenues(agencyID string, date string) (float64, error)
 	SumCosts(agencyID string, date string) (float64, error)
 	SumFixedCostsInRange(agencyID string, startDate string) (float64, error)
+	SumOneOffFixedCostsBetween(agencyID string, from string, to string) (float64, error)
+	SumOneOffVariableCostsBetween(agencyID string, from string, to string) (float64, error)
 	SumAllRevenuesInRange(agencyID string, startDate string) (float64, error)
 	SumAllCostsInRange(agencyID string, startDate string) (float64, error)
 	GetGroupedFixedCosts(agencyID string, startDate string) (map[string]float64, error)
+	MonthlyTotals(agencyID string, from string, to string) ([]MonthlyTotalEntity, error)
 }
 
 type postgresFinanceRepository struct {
@@ -26,26 +69,146 @@ func NewFinanceRepository(db *sql.DB) FinanceRepository {
 	return &postgresFinanceRepository{db: db}
 }
 
-func (r *postgresFinanceRepository) AddRevenue(agencyID string, amount float64, source string) error {
+func (r *postgresFinanceRepository) AddRevenue(agencyID string, date string, amount float64, source string) (string, error) {
 	id := uuid.New().String()
-	date := time.Now().Format("2006-01-02")
 	_, err := r.db.Exec(`
 		INSERT INTO daily_revenues (id, agency_id, date, amount, source)
 		VALUES ($1, $2, $3, $4, $5)
 	`, id, agencyID, date, amount, source)
-	return err
+	if err != nil {
+		return "", err
+	}
+	return id, nil
 }
 
-func (r *postgresFinanceRepository) AddCost(agencyID string, amount float64, costType string, label string, category string) error {
+func (r *postgresFinanceRepository) AddCost(agencyID string, date string, amount float64, costType string, label string, category string) (string, error) {
 	id := uuid.New().String()
-	date := time.Now().Format("2006-01-02")
 	_, err := r.db.Exec(`
 		INSERT INTO daily_costs (id, agency_id, date, amount, type, label, category)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`, id, agencyID, date, amount, costType, label, category)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *postgresFinanceRepository) ListRevenues(agencyID string, from string, to string) ([]RevenueEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, amount, source, created_at FROM daily_revenues
+		WHERE agency_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date DESC, created_at DESC
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revenues []RevenueEntity
+	for rows.Next() {
+		rev := RevenueEntity{AgencyID: agencyID}
+		var date time.Time
+		if err := rows.Scan(&rev.ID, &date, &rev.Amount, &rev.Source, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		rev.Date = date.Format("2006-01-02")
+		revenues = append(revenues, rev)
+	}
+	return revenues, rows.Err()
+}
+
+func (r *postgresFinanceRepository) ListCosts(agencyID string, from string, to string) ([]CostEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, amount, type, label, category, recurring_cost_id, created_at FROM daily_costs
+		WHERE agency_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date DESC, created_at DESC
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []CostEntity
+	for rows.Next() {
+		cost := CostEntity{AgencyID: agencyID}
+		var date time.Time
+		if err := rows.Scan(&cost.ID, &date, &cost.Amount, &cost.Type, &cost.Label, &cost.Category, &cost.RecurringCostID, &cost.CreatedAt); err != nil {
+			return nil, err
+		}
+		cost.Date = date.Format("2006-01-02")
+		costs = append(costs, cost)
+	}
+	return costs, rows.Err()
+}
+
+func (r *postgresFinanceRepository) GetRevenue(agencyID string, id string) (*RevenueEntity, error) {
+	rev := RevenueEntity{AgencyID: agencyID}
+	var date time.Time
+	err := r.db.QueryRow(`
+		SELECT id, date, amount, source, created_at FROM daily_revenues
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id).Scan(&rev.ID, &date, &rev.Amount, &rev.Source, &rev.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	rev.Date = date.Format("2006-01-02")
+	return &rev, nil
+}
+
+func (r *postgresFinanceRepository) GetCost(agencyID string, id string) (*CostEntity, error) {
+	cost := CostEntity{AgencyID: agencyID}
+	var date time.Time
+	err := r.db.QueryRow(`
+		SELECT id, date, amount, type, label, category, recurring_cost_id, created_at FROM daily_costs
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id).Scan(&cost.ID, &date, &cost.Amount, &cost.Type, &cost.Label, &cost.Category, &cost.RecurringCostID, &cost.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	cost.Date = date.Format("2006-01-02")
+	return &cost, nil
+}
+
+func (r *postgresFinanceRepository) UpdateRevenue(rev RevenueEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE daily_revenues SET date = $3, amount = $4, source = $5
+		WHERE agency_id = $1 AND id = $2
+	`, rev.AgencyID, rev.ID, rev.Date, rev.Amount, rev.Source)
 	return err
 }
 
+func (r *postgresFinanceRepository) UpdateCost(cost CostEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE daily_costs SET date = $3, amount = $4, type = $5, label = $6, category = $7
+		WHERE agency_id = $1 AND id = $2
+	`, cost.AgencyID, cost.ID, cost.Date, cost.Amount, cost.Type, cost.Label, cost.Category)
+	return err
+}
+
+func (r *postgresFinanceRepository) DeleteRevenue(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM daily_revenues WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresFinanceRepository) DeleteCost(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM daily_costs WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 func (r *postgresFinanceRepository) SumRevenues(agencyID string, date string) (float64, error) {
 	var total float64
 	err := r.db.QueryRow(`
@@ -73,6 +236,26 @@ func (r *postgresFinanceRepository) SumFixedCostsInRange(agencyID string, startD
 	return total, err
 }
 
+// SumOneOffFixedCostsBetween sums fixed costs that were not posted from a
+// recurring template, between from and to inclusive.
+func (r *postgresFinanceRepository) SumOneOffFixedCostsBetween(agencyID string, from string, to string) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(amount), 0) FROM daily_costs
+		WHERE agency_id = $1 AND type = 'fixed' AND recurring_cost_id IS NULL AND date >= $2 AND date <= $3
+	`, agencyID, from, to).Scan(&total)
+	return total, err
+}
+
+func (r *postgresFinanceRepository) SumOneOffVariableCostsBetween(agencyID string, from string, to string) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(amount), 0) FROM daily_costs
+		WHERE agency_id = $1 AND type = 'variable' AND recurring_cost_id IS NULL AND date >= $2 AND date <= $3
+	`, agencyID, from, to).Scan(&total)
+	return total, err
+}
+
 func (r *postgresFinanceRepository) SumAllRevenuesInRange(agencyID string, startDate string) (float64, error) {
 	var total float64
 	err := r.db.QueryRow(`
@@ -113,3 +296,36 @@ func (r *postgresFinanceRepository) GetGroupedFixedCosts(agencyID string, startD
 	}
 	return result, nil
 }
+
+// MonthlyTotals returns revenue and cost totals for every calendar month
+// between from and to inclusive, with zeros for months without entries.
+func (r *postgresFinanceRepository) MonthlyTotals(agencyID string, from string, to string) ([]MonthlyTotalEntity, error) {
+	rows, err := r.db.Query(`
+		WITH months AS (
+			SELECT generate_series(date_trunc('month', $2::date), date_trunc('month', $3::date), interval '1 month')::date AS month
+		)
+		SELECT m.month,
+			COALESCE((SELECT SUM(amount) FROM daily_revenues WHERE agency_id = $1 AND date >= m.month AND date < m.month + interval '1 month'), 0),
+			COALESCE((SELECT SUM(amount) FROM daily_costs WHERE agency_id = $1 AND date >= m.month AND date < m.month + interval '1 month'), 0),
+			EXISTS (SELECT 1 FROM daily_revenues WHERE agency_id = $1 AND date >= m.month AND date < m.month + interval '1 month')
+				OR EXISTS (SELECT 1 FROM daily_costs WHERE agency_id = $1 AND date >= m.month AND date < m.month + interval '1 month')
+		FROM months m
+		ORDER BY m.month
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []MonthlyTotalEntity
+	for rows.Next() {
+		var t MonthlyTotalEntity
+		var month time.Time
+		if err := rows.Scan(&month, &t.Revenue, &t.Costs, &t.HasEntries); err != nil {
+			return nil, err
+		}
+		t.Month = month.Format("2006-01")
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
@@ -7,7 +7,9 @@ import (
 )
 
 type FounderRepository interface {
-	EnsureUser(id string, email string) error
+	// EnsureUser creates the founder if they don't exist yet and reports
+	// whether it did.
+	EnsureUser(id string, email string) (bool, error)
 }
 
 type postgresFounderRepository struct {
@@ -18,18 +20,14 @@ func NewFounderRepository(db *sql.DB) FounderRepository {
 	return &postgresFounderRepository{db: db}
 }
 
-func (r *postgresFounderRepository) EnsureUser(id string, email string) error {
-	var exists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM founders WHERE id=$1)", id).Scan(&exists)
+func (r *postgresFounderRepository) EnsureUser(id string, email string) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO founders (id, email, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`, id, email, time.Now())
 	if err != nil {
-		return err
+		return false, fmt.Errorf("failed to create user: %v", err)
 	}
-
-	if !exists {
-		_, err = r.db.Exec("INSERT INTO founders (id, email, created_at) VALUES ($1, $2, $3)", id, email, time.Now())
-		if err != nil {
-			return fmt.Errorf("failed to create user: %v", err)
-		}
-	}
-	return nil
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceEntity is an issued invoice. Outstanding invoices (PaidOn nil) are
+// expected to be collected on their due date.
+type InvoiceEntity struct {
+	ID        string
+	AgencyID  string
+	ClientID  *string
+	Number    string
+	Amount    float64
+	DueDate   string
+	PaidOn    *string
+	CreatedAt time.Time
+}
+
+type InvoiceRepository interface {
+	Create(invoice InvoiceEntity) (*InvoiceEntity, error)
+	List(agencyID string, outstandingOnly bool) ([]InvoiceEntity, error)
+	Get(agencyID string, id string) (*InvoiceEntity, error)
+	Update(invoice InvoiceEntity) error
+	Delete(agencyID string, id string) (bool, error)
+}
+
+type postgresInvoiceRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceRepository(db *sql.DB) InvoiceRepository {
+	return &postgresInvoiceRepository{db: db}
+}
+
+func (r *postgresInvoiceRepository) Create(inv InvoiceEntity) (*InvoiceEntity, error) {
+	inv.ID = uuid.New().String()
+	inv.CreatedAt = time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO invoices (id, agency_id, client_id, number, amount, due_date, paid_on, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, inv.ID, inv.AgencyID, inv.ClientID, inv.Number, inv.Amount, inv.DueDate, inv.PaidOn, inv.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *postgresInvoiceRepository) List(agencyID string, outstandingOnly bool) ([]InvoiceEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, client_id, number, amount, due_date, paid_on, created_at FROM invoices
+		WHERE agency_id = $1 AND (NOT $2 OR paid_on IS NULL)
+		ORDER BY due_date, created_at
+	`, agencyID, outstandingOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []InvoiceEntity
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		inv.AgencyID = agencyID
+		invoices = append(invoices, *inv)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *postgresInvoiceRepository) Get(agencyID string, id string) (*InvoiceEntity, error) {
+	inv, err := scanInvoice(r.db.QueryRow(`
+		SELECT id, client_id, number, amount, due_date, paid_on, created_at FROM invoices
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	inv.AgencyID = agencyID
+	return inv, nil
+}
+
+func (r *postgresInvoiceRepository) Update(inv InvoiceEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE invoices SET number = $3, amount = $4, due_date = $5, paid_on = $6
+		WHERE agency_id = $1 AND id = $2
+	`, inv.AgencyID, inv.ID, inv.Number, inv.Amount, inv.DueDate, inv.PaidOn)
+	return err
+}
+
+func (r *postgresInvoiceRepository) Delete(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM invoices WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanInvoice(row rowScanner) (*InvoiceEntity, error) {
+	var inv InvoiceEntity
+	var due time.Time
+	var paid sql.NullTime
+	if err := row.Scan(&inv.ID, &inv.ClientID, &inv.Number, &inv.Amount, &due, &paid, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+	inv.DueDate = due.Format("2006-01-02")
+	if paid.Valid {
+		p := paid.Time.Format("2006-01-02")
+		inv.PaidOn = &p
+	}
+	return &inv, nil
+}
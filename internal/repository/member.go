@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemberEntity struct {
+	FounderID string
+	Email     string
+	Role      string
+	CreatedAt time.Time
+}
+
+type InvitationEntity struct {
+	ID        string
+	AgencyID  string
+	Email     string
+	Role      string
+	InvitedBy string
+	CreatedAt time.Time
+}
+
+type MemberRepository interface {
+	ListMembers(agencyID string) ([]MemberEntity, error)
+	IsMemberByEmail(agencyID string, email string) (bool, error)
+	// CreateInvitation returns ErrDuplicate if email already has a pending
+	// invitation to the agency.
+	CreateInvitation(agencyID string, email string, role string, invitedBy string) (*InvitationEntity, error)
+	ListPendingInvitations(agencyID string) ([]InvitationEntity, error)
+	AcceptInvitations(founderID string, email string) error
+	// AcceptForExistingUsers accepts the pending invitation straight away if
+	// its email already belongs to a founder.
+	AcceptForExistingUsers(invitationID string) error
+}
+
+type postgresMemberRepository struct {
+	db *sql.DB
+}
+
+func NewMemberRepository(db *sql.DB) MemberRepository {
+	return &postgresMemberRepository{db: db}
+}
+
+func (r *postgresMemberRepository) ListMembers(agencyID string) ([]MemberEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT m.founder_id, f.email, m.role, m.created_at
+		FROM agency_members m
+		JOIN founders f ON f.id = m.founder_id
+		WHERE m.agency_id = $1
+		ORDER BY m.created_at
+	`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []MemberEntity
+	for rows.Next() {
+		var m MemberEntity
+		if err := rows.Scan(&m.FounderID, &m.Email, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *postgresMemberRepository) IsMemberByEmail(agencyID string, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM agency_members m
+			JOIN founders f ON f.id = m.founder_id
+			WHERE m.agency_id = $1 AND lower(f.email) = lower($2)
+		)
+	`, agencyID, email).Scan(&exists)
+	return exists, err
+}
+
+func (r *postgresMemberRepository) CreateInvitation(agencyID string, email string, role string, invitedBy string) (*InvitationEntity, error) {
+	id := uuid.New().String()
+	now := time.Now()
+	email = strings.TrimSpace(email)
+	_, err := r.db.Exec(`
+		INSERT INTO agency_invitations (id, agency_id, email, role, invited_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, id, agencyID, email, role, invitedBy, now)
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	} else if err != nil {
+		return nil, err
+	}
+	return &InvitationEntity{
+		ID:        id,
+		AgencyID:  agencyID,
+		Email:     email,
+		Role:      role,
+		InvitedBy: invitedBy,
+		CreatedAt: now,
+	}, nil
+}
+
+func (r *postgresMemberRepository) ListPendingInvitations(agencyID string) ([]InvitationEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, email, role, invited_by, created_at
+		FROM agency_invitations
+		WHERE agency_id = $1 AND accepted_at IS NULL
+		ORDER BY created_at
+	`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []InvitationEntity
+	for rows.Next() {
+		inv := InvitationEntity{AgencyID: agencyID}
+		if err := rows.Scan(&inv.ID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.CreatedAt); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+// AcceptInvitations turns every pending invitation for email into a membership.
+func (r *postgresMemberRepository) AcceptInvitations(founderID string, email string) error {
+	_, err := r.db.Exec(`
+		WITH accepted AS (
+			UPDATE agency_invitations SET accepted_at = $3
+			WHERE lower(email) = lower($2) AND accepted_at IS NULL
+			RETURNING agency_id, role
+		)
+		INSERT INTO agency_members (agency_id, founder_id, role)
+		SELECT agency_id, $1, role FROM accepted
+		ON CONFLICT (agency_id, founder_id) DO NOTHING
+	`, founderID, email, time.Now())
+	return err
+}
+
+func (r *postgresMemberRepository) AcceptForExistingUsers(invitationID string) error {
+	_, err := r.db.Exec(`
+		WITH accepted AS (
+			UPDATE agency_invitations i SET accepted_at = $2
+			WHERE i.id = $1 AND i.accepted_at IS NULL
+				AND EXISTS (SELECT 1 FROM founders f WHERE lower(f.email) = lower(i.email))
+			RETURNING i.agency_id, i.email, i.role
+		)
+		INSERT INTO agency_members (agency_id, founder_id, role)
+		SELECT a.agency_id, f.id, a.role FROM accepted a
+		JOIN founders f ON lower(f.email) = lower(a.email)
+		ON CONFLICT (agency_id, founder_id) DO NOTHING
+	`, invitationID, time.Now())
+	return err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DirectionInflow  = "inflow"
+	DirectionOutflow = "outflow"
+)
+
+// PlannedItemEntity is a known future cash movement that isn't in the ledger
+// yet, such as a tax payment or an expected project fee.
+type PlannedItemEntity struct {
+	ID        string
+	AgencyID  string
+	Date      string
+	Amount    float64
+	Direction string
+	Label     string
+	CreatedAt time.Time
+}
+
+type PlannedItemRepository interface {
+	Create(agencyID string, date string, amount float64, direction string, label string) (*PlannedItemEntity, error)
+	ListBetween(agencyID string, from string, to string) ([]PlannedItemEntity, error)
+	Delete(agencyID string, id string) (bool, error)
+}
+
+type postgresPlannedItemRepository struct {
+	db *sql.DB
+}
+
+func NewPlannedItemRepository(db *sql.DB) PlannedItemRepository {
+	return &postgresPlannedItemRepository{db: db}
+}
+
+func (r *postgresPlannedItemRepository) Create(agencyID string, date string, amount float64, direction string, label string) (*PlannedItemEntity, error) {
+	item := PlannedItemEntity{
+		ID:        uuid.New().String(),
+		AgencyID:  agencyID,
+		Date:      date,
+		Amount:    amount,
+		Direction: direction,
+		Label:     label,
+		CreatedAt: time.Now(),
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO planned_cash_items (id, agency_id, date, amount, direction, label, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, item.ID, agencyID, date, amount, direction, label, item.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *postgresPlannedItemRepository) ListBetween(agencyID string, from string, to string) ([]PlannedItemEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, date, amount, direction, label, created_at FROM planned_cash_items
+		WHERE agency_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date, created_at
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []PlannedItemEntity
+	for rows.Next() {
+		item := PlannedItemEntity{AgencyID: agencyID}
+		var date time.Time
+		if err := rows.Scan(&item.ID, &date, &item.Amount, &item.Direction, &item.Label, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		item.Date = date.Format("2006-01-02")
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *postgresPlannedItemRepository) Delete(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM planned_cash_items WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
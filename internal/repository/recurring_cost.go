@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecurringCostEntity struct {
+	ID            string
+	AgencyID      string
+	Amount        float64
+	Label         string
+	Category      string
+	Cadence       string
+	StartDate     string
+	EndDate       *string
+	PostedThrough *string
+	CreatedAt     time.Time
+}
+
+type RecurringCostRepository interface {
+	Create(cost RecurringCostEntity) (*RecurringCostEntity, error)
+	List(agencyID string) ([]RecurringCostEntity, error)
+	ListActive() ([]RecurringCostEntity, error)
+	Get(agencyID string, id string) (*RecurringCostEntity, error)
+	Update(cost RecurringCostEntity) error
+	Archive(agencyID string, id string) (bool, error)
+	// Post inserts a fixed cost for each date not already posted and moves
+	// posted_through on to through. It returns the new costs' ids.
+	Post(cost RecurringCostEntity, dates []string, through string) ([]string, error)
+}
+
+type postgresRecurringCostRepository struct {
+	db *sql.DB
+}
+
+func NewRecurringCostRepository(db *sql.DB) RecurringCostRepository {
+	return &postgresRecurringCostRepository{db: db}
+}
+
+const recurringCostColumns = `id, agency_id, amount, label, category, cadence, start_date, end_date, posted_through, created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecurringCost(row rowScanner) (*RecurringCostEntity, error) {
+	var c RecurringCostEntity
+	var start time.Time
+	var end, posted sql.NullTime
+	if err := row.Scan(&c.ID, &c.AgencyID, &c.Amount, &c.Label, &c.Category, &c.Cadence, &start, &end, &posted, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	c.StartDate = start.Format("2006-01-02")
+	if end.Valid {
+		d := end.Time.Format("2006-01-02")
+		c.EndDate = &d
+	}
+	if posted.Valid {
+		d := posted.Time.Format("2006-01-02")
+		c.PostedThrough = &d
+	}
+	return &c, nil
+}
+
+func (r *postgresRecurringCostRepository) Create(c RecurringCostEntity) (*RecurringCostEntity, error) {
+	c.ID = uuid.New().String()
+	c.CreatedAt = time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO recurring_costs (id, agency_id, amount, label, category, cadence, start_date, end_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, c.ID, c.AgencyID, c.Amount, c.Label, c.Category, c.Cadence, c.StartDate, c.EndDate, c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *postgresRecurringCostRepository) List(agencyID string) ([]RecurringCostEntity, error) {
+	return r.query(`
+		SELECT `+recurringCostColumns+` FROM recurring_costs
+		WHERE agency_id = $1 AND archived_at IS NULL
+		ORDER BY created_at
+	`, agencyID)
+}
+
+// ListActive returns every agency's templates that may still have postings
+// due.
+func (r *postgresRecurringCostRepository) ListActive() ([]RecurringCostEntity, error) {
+	return r.query(`
+		SELECT ` + recurringCostColumns + ` FROM recurring_costs
+		WHERE archived_at IS NULL
+		  AND (end_date IS NULL OR posted_through IS NULL OR posted_through < end_date)
+		ORDER BY agency_id, created_at
+	`)
+}
+
+func (r *postgresRecurringCostRepository) query(query string, args ...interface{}) ([]RecurringCostEntity, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []RecurringCostEntity
+	for rows.Next() {
+		c, err := scanRecurringCost(rows)
+		if err != nil {
+			return nil, err
+		}
+		costs = append(costs, *c)
+	}
+	return costs, rows.Err()
+}
+
+func (r *postgresRecurringCostRepository) Get(agencyID string, id string) (*RecurringCostEntity, error) {
+	c, err := scanRecurringCost(r.db.QueryRow(`
+		SELECT `+recurringCostColumns+` FROM recurring_costs
+		WHERE agency_id = $1 AND id = $2 AND archived_at IS NULL
+	`, agencyID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *postgresRecurringCostRepository) Update(c RecurringCostEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE recurring_costs SET amount = $3, label = $4, category = $5, end_date = $6
+		WHERE agency_id = $1 AND id = $2
+	`, c.AgencyID, c.ID, c.Amount, c.Label, c.Category, c.EndDate)
+	return err
+}
+
+// Archive stops future postings. Costs already posted stay in the ledger.
+func (r *postgresRecurringCostRepository) Archive(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE recurring_costs SET archived_at = $3
+		WHERE agency_id = $1 AND id = $2 AND archived_at IS NULL
+	`, agencyID, id, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Post inserts a fixed cost for each date and advances posted_through. Dates
+// that were already posted are skipped, so overlapping runs are harmless.
+func (r *postgresRecurringCostRepository) Post(c RecurringCostEntity, dates []string, through string) ([]string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var posted []string
+	for _, date := range dates {
+		id := uuid.New().String()
+		res, err := tx.Exec(`
+			INSERT INTO daily_costs (id, agency_id, date, amount, type, label, category, recurring_cost_id)
+			VALUES ($1, $2, $3, $4, 'fixed', $5, $6, $7)
+			ON CONFLICT (recurring_cost_id, date) DO NOTHING
+		`, id, c.AgencyID, date, c.Amount, c.Label, c.Category, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			posted = append(posted, id)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE recurring_costs SET posted_through = $2
+		WHERE id = $1 AND (posted_through IS NULL OR posted_through < $2)
+	`, c.ID, through)
+	if err != nil {
+		return nil, err
+	}
+
+	return posted, tx.Commit()
+}
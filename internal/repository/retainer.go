@@ -6,8 +6,19 @@ import (
 	"github.com/google/uuid"
 )
 
+type RetainerEntity struct {
+	ID               string
+	ClientID         string
+	MonthlyAmount    float64
+	ChurnProbability float64
+	// BillingDay is the day of the month the retainer is paid, 1-28
+	BillingDay int
+}
+
 type RetainerRepository interface {
-	Create(agencyID string, clientID string, amount float64) error
+	Create(agencyID string, clientID string, amount float64, churnProbability float64, billingDay int) (*RetainerEntity, error)
+	ListActive(agencyID string) ([]RetainerEntity, error)
+	Update(agencyID string, id string, churnProbability *float64, billingDay *int) (bool, error)
 	SumActiveRetainers(agencyID string) (float64, error)
 	GetMaxRetainer(agencyID string) (float64, error)
 	HasActiveRetainer(clientID string) (bool, error)
@@ -21,13 +32,59 @@ func NewRetainerRepository(db *sql.DB) RetainerRepository {
 	return &postgresRetainerRepository{db: db}
 }
 
-func (r *postgresRetainerRepository) Create(agencyID string, clientID string, amount float64) error {
-	id := uuid.New().String()
+func (r *postgresRetainerRepository) Create(agencyID string, clientID string, amount float64, churnProbability float64, billingDay int) (*RetainerEntity, error) {
+	retainer := RetainerEntity{
+		ID:               uuid.New().String(),
+		ClientID:         clientID,
+		MonthlyAmount:    amount,
+		ChurnProbability: churnProbability,
+		BillingDay:       billingDay,
+	}
 	_, err := r.db.Exec(`
-		INSERT INTO retainers (id, agency_id, client_id, monthly_amount, active)
-		VALUES ($1, $2, $3, $4, true)
-	`, id, agencyID, clientID, amount)
-	return err
+		INSERT INTO retainers (id, agency_id, client_id, monthly_amount, churn_probability, billing_day, active)
+		VALUES ($1, $2, $3, $4, $5, $6, true)
+	`, retainer.ID, agencyID, clientID, amount, churnProbability, billingDay)
+	if err != nil {
+		return nil, err
+	}
+	return &retainer, nil
+}
+
+func (r *postgresRetainerRepository) ListActive(agencyID string) ([]RetainerEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, client_id, monthly_amount, churn_probability, billing_day FROM retainers
+		WHERE agency_id = $1 AND active = true
+		ORDER BY created_at
+	`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var retainers []RetainerEntity
+	for rows.Next() {
+		var ret RetainerEntity
+		if err := rows.Scan(&ret.ID, &ret.ClientID, &ret.MonthlyAmount, &ret.ChurnProbability, &ret.BillingDay); err != nil {
+			return nil, err
+		}
+		retainers = append(retainers, ret)
+	}
+	return retainers, rows.Err()
+}
+
+// Update changes the fields that are non-nil.
+func (r *postgresRetainerRepository) Update(agencyID string, id string, churnProbability *float64, billingDay *int) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE retainers SET
+			churn_probability = COALESCE($3, churn_probability),
+			billing_day = COALESCE($4, billing_day)
+		WHERE agency_id = $1 AND id = $2 AND active = true
+	`, agencyID, id, churnProbability, billingDay)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
 }
 
 func (r *postgresRetainerRepository) SumActiveRetainers(agencyID string) (float64, error) {
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScenarioEntity struct {
+	ID          string
+	AgencyID    string
+	Name        string
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Adjustments []ScenarioAdjustmentEntity
+}
+
+// ScenarioAdjustmentEntity is one hypothetical change. Which optional fields
+// are set depends on Kind.
+type ScenarioAdjustmentEntity struct {
+	Kind            string
+	Label           string
+	Amount          *float64
+	CapacityHours   *float64
+	ClientID        *string
+	RecurringCostID *string
+	StartDate       string
+}
+
+type ScenarioRepository interface {
+	Create(scenario ScenarioEntity) (*ScenarioEntity, error)
+	List(agencyID string) ([]ScenarioEntity, error)
+	Get(agencyID string, id string) (*ScenarioEntity, error)
+	Replace(scenario ScenarioEntity) error
+	Delete(agencyID string, id string) (bool, error)
+}
+
+type postgresScenarioRepository struct {
+	db *sql.DB
+}
+
+func NewScenarioRepository(db *sql.DB) ScenarioRepository {
+	return &postgresScenarioRepository{db: db}
+}
+
+func (r *postgresScenarioRepository) Create(s ScenarioEntity) (*ScenarioEntity, error) {
+	s.ID = uuid.New().String()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = s.CreatedAt
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO scenarios (id, agency_id, name, description, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, s.ID, s.AgencyID, s.Name, s.Description, s.CreatedBy, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAdjustments(tx, s.ID, s.Adjustments); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *postgresScenarioRepository) List(agencyID string) ([]ScenarioEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, description, created_by, created_at, updated_at FROM scenarios
+		WHERE agency_id = $1
+		ORDER BY created_at
+	`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scenarios []ScenarioEntity
+	for rows.Next() {
+		s := ScenarioEntity{AgencyID: agencyID}
+		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range scenarios {
+		if scenarios[i].Adjustments, err = r.listAdjustments(scenarios[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return scenarios, nil
+}
+
+func (r *postgresScenarioRepository) Get(agencyID string, id string) (*ScenarioEntity, error) {
+	s := ScenarioEntity{AgencyID: agencyID}
+	err := r.db.QueryRow(`
+		SELECT id, name, description, created_by, created_at, updated_at FROM scenarios
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id).Scan(&s.ID, &s.Name, &s.Description, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if s.Adjustments, err = r.listAdjustments(s.ID); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Replace overwrites the scenario's name, description and full list of
+// adjustments.
+func (r *postgresScenarioRepository) Replace(s ScenarioEntity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE scenarios SET name = $3, description = $4, updated_at = $5
+		WHERE agency_id = $1 AND id = $2
+	`, s.AgencyID, s.ID, s.Name, s.Description, time.Now())
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM scenario_adjustments WHERE scenario_id = $1`, s.ID); err != nil {
+		return err
+	}
+	if err := insertAdjustments(tx, s.ID, s.Adjustments); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *postgresScenarioRepository) Delete(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM scenarios WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func insertAdjustments(tx *sql.Tx, scenarioID string, adjustments []ScenarioAdjustmentEntity) error {
+	for i, a := range adjustments {
+		_, err := tx.Exec(`
+			INSERT INTO scenario_adjustments (id, scenario_id, position, kind, label, amount, capacity_hours, client_id, recurring_cost_id, start_date)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, uuid.New().String(), scenarioID, i, a.Kind, a.Label, a.Amount, a.CapacityHours, a.ClientID, a.RecurringCostID, a.StartDate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresScenarioRepository) listAdjustments(scenarioID string) ([]ScenarioAdjustmentEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT kind, label, amount, capacity_hours, client_id, recurring_cost_id, start_date
+		FROM scenario_adjustments
+		WHERE scenario_id = $1
+		ORDER BY position
+	`, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []ScenarioAdjustmentEntity
+	for rows.Next() {
+		var a ScenarioAdjustmentEntity
+		var amount, hours sql.NullFloat64
+		var start time.Time
+		if err := rows.Scan(&a.Kind, &a.Label, &amount, &hours, &a.ClientID, &a.RecurringCostID, &start); err != nil {
+			return nil, err
+		}
+		if amount.Valid {
+			a.Amount = &amount.Float64
+		}
+		if hours.Valid {
+			a.CapacityHours = &hours.Float64
+		}
+		a.StartDate = start.Format("2006-01-02")
+		adjustments = append(adjustments, a)
+	}
+	return adjustments, rows.Err()
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ScoreSnapshotEntity is an agency's reality score as computed on Date.
+// Inputs is the JSON-encoded measurements the score was computed from.
+type ScoreSnapshotEntity struct {
+	AgencyID            string
+	Date                string
+	Score               int
+	Status              string
+	PrimaryRisk         string
+	ProfileVersion      string
+	RetainerSafety      int
+	Runway              int
+	ClientConcentration int
+	Profitability       int
+	CapacityPressure    int
+	Inputs              []byte
+	CreatedAt           time.Time
+}
+
+type ScoreSnapshotRepository interface {
+	// Upsert replaces any snapshot already taken that day.
+	Upsert(snapshot ScoreSnapshotEntity) error
+	ListBetween(agencyID string, from string, to string) ([]ScoreSnapshotEntity, error)
+	GetOnOrBefore(agencyID string, date string) (*ScoreSnapshotEntity, error)
+}
+
+type postgresScoreSnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewScoreSnapshotRepository(db *sql.DB) ScoreSnapshotRepository {
+	return &postgresScoreSnapshotRepository{db: db}
+}
+
+func (r *postgresScoreSnapshotRepository) Upsert(s ScoreSnapshotEntity) error {
+	_, err := r.db.Exec(`
+		INSERT INTO reality_score_snapshots (agency_id, date, score, status, primary_risk, profile_version,
+			retainer_safety, runway, client_concentration, profitability, capacity_pressure, inputs, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (agency_id, date) DO UPDATE SET
+			score = EXCLUDED.score,
+			status = EXCLUDED.status,
+			primary_risk = EXCLUDED.primary_risk,
+			profile_version = EXCLUDED.profile_version,
+			retainer_safety = EXCLUDED.retainer_safety,
+			runway = EXCLUDED.runway,
+			client_concentration = EXCLUDED.client_concentration,
+			profitability = EXCLUDED.profitability,
+			capacity_pressure = EXCLUDED.capacity_pressure,
+			inputs = EXCLUDED.inputs,
+			created_at = EXCLUDED.created_at
+	`, s.AgencyID, s.Date, s.Score, s.Status, s.PrimaryRisk, s.ProfileVersion,
+		s.RetainerSafety, s.Runway, s.ClientConcentration, s.Profitability, s.CapacityPressure, s.Inputs, time.Now())
+	return err
+}
+
+const scoreSnapshotColumns = `date, score, status, primary_risk, profile_version,
+	retainer_safety, runway, client_concentration, profitability, capacity_pressure, inputs, created_at`
+
+func (r *postgresScoreSnapshotRepository) ListBetween(agencyID string, from string, to string) ([]ScoreSnapshotEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT `+scoreSnapshotColumns+` FROM reality_score_snapshots
+		WHERE agency_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date
+	`, agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []ScoreSnapshotEntity
+	for rows.Next() {
+		s, err := scanScoreSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		s.AgencyID = agencyID
+		snapshots = append(snapshots, *s)
+	}
+	return snapshots, rows.Err()
+}
+
+func (r *postgresScoreSnapshotRepository) GetOnOrBefore(agencyID string, date string) (*ScoreSnapshotEntity, error) {
+	s, err := scanScoreSnapshot(r.db.QueryRow(`
+		SELECT `+scoreSnapshotColumns+` FROM reality_score_snapshots
+		WHERE agency_id = $1 AND date <= $2
+		ORDER BY date DESC
+		LIMIT 1
+	`, agencyID, date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s.AgencyID = agencyID
+	return s, nil
+}
+
+func scanScoreSnapshot(row rowScanner) (*ScoreSnapshotEntity, error) {
+	var s ScoreSnapshotEntity
+	var date time.Time
+	err := row.Scan(&date, &s.Score, &s.Status, &s.PrimaryRisk, &s.ProfileVersion,
+		&s.RetainerSafety, &s.Runway, &s.ClientConcentration, &s.Profitability, &s.CapacityPressure, &s.Inputs, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Date = date.Format("2006-01-02")
+	return &s, nil
+}
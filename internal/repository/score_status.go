@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ScoreStatusRepository interface {
+	// Swap records status as the agency's current reality score status. It
+	// reports whether that's a change and, if so, the status it replaced,
+	// which is nil the first time one is recorded. Concurrent swaps to the
+	// same status report a single change.
+	Swap(agencyID string, status string, at time.Time) (previous *string, changed bool, err error)
+}
+
+type postgresScoreStatusRepository struct {
+	db *sql.DB
+}
+
+func NewScoreStatusRepository(db *sql.DB) ScoreStatusRepository {
+	return &postgresScoreStatusRepository{db: db}
+}
+
+func (r *postgresScoreStatusRepository) Swap(agencyID string, status string, at time.Time) (*string, bool, error) {
+	var previous *string
+	err := r.db.QueryRow(`
+		WITH previous AS (
+			SELECT status FROM reality_score_statuses WHERE agency_id = $1 FOR UPDATE
+		)
+		INSERT INTO reality_score_statuses (agency_id, status, changed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (agency_id) DO UPDATE SET status = EXCLUDED.status, changed_at = EXCLUDED.changed_at
+		WHERE reality_score_statuses.status <> EXCLUDED.status
+		RETURNING (SELECT status FROM previous)
+	`, agencyID, status, at).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return previous, true, nil
+}
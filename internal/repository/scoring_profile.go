@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ScoringProfileEntity holds an agency's reality score configuration as
+// JSON. Profile is nil when the agency uses the defaults.
+type ScoringProfileEntity struct {
+	AgencyID  string
+	Profile   []byte
+	Version   int
+	UpdatedBy *string
+	UpdatedAt time.Time
+}
+
+type ScoringProfileRepository interface {
+	// Get returns nil when the agency has never saved a profile.
+	Get(agencyID string) (*ScoringProfileEntity, error)
+	// Save stores profile (nil to revert to the defaults) and bumps the version.
+	Save(agencyID string, profile []byte, updatedBy string) (*ScoringProfileEntity, error)
+}
+
+type postgresScoringProfileRepository struct {
+	db *sql.DB
+}
+
+func NewScoringProfileRepository(db *sql.DB) ScoringProfileRepository {
+	return &postgresScoringProfileRepository{db: db}
+}
+
+func (r *postgresScoringProfileRepository) Get(agencyID string) (*ScoringProfileEntity, error) {
+	p := ScoringProfileEntity{AgencyID: agencyID}
+	err := r.db.QueryRow(`
+		SELECT profile, version, updated_by, updated_at FROM scoring_profiles
+		WHERE agency_id = $1
+	`, agencyID).Scan(&p.Profile, &p.Version, &p.UpdatedBy, &p.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *postgresScoringProfileRepository) Save(agencyID string, profile []byte, updatedBy string) (*ScoringProfileEntity, error) {
+	p := ScoringProfileEntity{AgencyID: agencyID, Profile: profile, UpdatedBy: &updatedBy}
+	err := r.db.QueryRow(`
+		INSERT INTO scoring_profiles (agency_id, profile, version, updated_by, updated_at)
+		VALUES ($1, $2, 1, $3, now())
+		ON CONFLICT (agency_id) DO UPDATE SET
+			profile = EXCLUDED.profile,
+			version = scoring_profiles.version + 1,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING version, updated_at
+	`, agencyID, profile, updatedBy).Scan(&p.Version, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
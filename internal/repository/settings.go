@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AgencySettingsEntity struct {
+	AgencyID             string
+	Timezone             string
+	FiscalYearStartMonth int
+	LookbackDays         int
+	MonthlyCapacityHours float64
+	FutureToleranceDays  int
+	BurnAverageMonths    int
+	CashSafetyFloor      float64
+}
+
+// DefaultAgencySettings mirrors the column defaults, for agencies that have
+// never saved settings.
+func DefaultAgencySettings(agencyID string) AgencySettingsEntity {
+	return AgencySettingsEntity{
+		AgencyID:             agencyID,
+		Timezone:             "UTC",
+		FiscalYearStartMonth: 1,
+		LookbackDays:         30,
+		MonthlyCapacityHours: 160,
+		FutureToleranceDays:  0,
+		BurnAverageMonths:    3,
+		CashSafetyFloor:      0,
+	}
+}
+
+type SettingsRepository interface {
+	Get(agencyID string) (*AgencySettingsEntity, error)
+	Save(settings AgencySettingsEntity) error
+}
+
+type postgresSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewSettingsRepository(db *sql.DB) SettingsRepository {
+	return &postgresSettingsRepository{db: db}
+}
+
+func (r *postgresSettingsRepository) Get(agencyID string) (*AgencySettingsEntity, error) {
+	s := AgencySettingsEntity{AgencyID: agencyID}
+	err := r.db.QueryRow(`
+		SELECT timezone, fiscal_year_start_month, lookback_days, monthly_capacity_hours, future_entry_tolerance_days, burn_average_months, cash_safety_floor
+		FROM agency_settings
+		WHERE agency_id = $1
+	`, agencyID).Scan(&s.Timezone, &s.FiscalYearStartMonth, &s.LookbackDays, &s.MonthlyCapacityHours, &s.FutureToleranceDays, &s.BurnAverageMonths, &s.CashSafetyFloor)
+
+	if err == sql.ErrNoRows {
+		d := DefaultAgencySettings(agencyID)
+		return &d, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *postgresSettingsRepository) Save(s AgencySettingsEntity) error {
+	_, err := r.db.Exec(`
+		INSERT INTO agency_settings (agency_id, timezone, fiscal_year_start_month, lookback_days, monthly_capacity_hours, future_entry_tolerance_days, burn_average_months, cash_safety_floor, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (agency_id) DO UPDATE SET
+			timezone = EXCLUDED.timezone,
+			fiscal_year_start_month = EXCLUDED.fiscal_year_start_month,
+			lookback_days = EXCLUDED.lookback_days,
+			monthly_capacity_hours = EXCLUDED.monthly_capacity_hours,
+			future_entry_tolerance_days = EXCLUDED.future_entry_tolerance_days,
+			burn_average_months = EXCLUDED.burn_average_months,
+			cash_safety_floor = EXCLUDED.cash_safety_floor,
+			updated_at = EXCLUDED.updated_at
+	`, s.AgencyID, s.Timezone, s.FiscalYearStartMonth, s.LookbackDays, s.MonthlyCapacityHours, s.FutureToleranceDays, s.BurnAverageMonths, s.CashSafetyFloor, time.Now())
+	return err
+}
@@ -2,13 +2,12 @@ package repository
 
 import (
 	"database/sql"
-	"time"
 
 	"github.com/google/uuid"
 )
 
 type TimeEntryRepository interface {
-	Add(agencyID string, clientID *string, hours float64) error
+	Add(agencyID string, clientID *string, date string, hours float64) error
 	SumHoursInRange(agencyID string, startDate string) (float64, error)
 }
 
@@ -20,9 +19,8 @@ func NewTimeEntryRepository(db *sql.DB) TimeEntryRepository {
 	return &postgresTimeEntryRepository{db: db}
 }
 
-func (r *postgresTimeEntryRepository) Add(agencyID string, clientID *string, hours float64) error {
+func (r *postgresTimeEntryRepository) Add(agencyID string, clientID *string, date string, hours float64) error {
 	id := uuid.New().String()
-	date := time.Now().Format("2006-01-02")
 	_, err := r.db.Exec(`
 		INSERT INTO time_entries (id, agency_id, client_id, date, hours)
 		VALUES ($1, $2, $3, $4, $5)
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WebhookSubscriptionEntity posts the listed event types to URL, signed
+// with Secret.
+type WebhookSubscriptionEntity struct {
+	ID         string
+	AgencyID   string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Enabled    bool
+	CreatedAt  time.Time
+}
+
+type WebhookSubscriptionRepository interface {
+	Create(sub WebhookSubscriptionEntity) (*WebhookSubscriptionEntity, error)
+	List(agencyID string) ([]WebhookSubscriptionEntity, error)
+	// ListForEvent returns the enabled subscriptions that want eventType.
+	ListForEvent(agencyID string, eventType string) ([]WebhookSubscriptionEntity, error)
+	Get(agencyID string, id string) (*WebhookSubscriptionEntity, error)
+	Update(sub WebhookSubscriptionEntity) error
+	Delete(agencyID string, id string) (bool, error)
+}
+
+type postgresWebhookSubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookSubscriptionRepository(db *sql.DB) WebhookSubscriptionRepository {
+	return &postgresWebhookSubscriptionRepository{db: db}
+}
+
+func (r *postgresWebhookSubscriptionRepository) Create(sub WebhookSubscriptionEntity) (*WebhookSubscriptionEntity, error) {
+	sub.ID = uuid.New().String()
+	sub.CreatedAt = time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO webhook_subscriptions (id, agency_id, url, secret, event_types, enabled, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, sub.ID, sub.AgencyID, sub.URL, sub.Secret, pq.Array(sub.EventTypes), sub.Enabled, sub.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *postgresWebhookSubscriptionRepository) List(agencyID string) ([]WebhookSubscriptionEntity, error) {
+	return r.query(agencyID, `
+		SELECT id, url, secret, event_types, enabled, created_at FROM webhook_subscriptions
+		WHERE agency_id = $1
+		ORDER BY created_at
+	`, agencyID)
+}
+
+func (r *postgresWebhookSubscriptionRepository) ListForEvent(agencyID string, eventType string) ([]WebhookSubscriptionEntity, error) {
+	return r.query(agencyID, `
+		SELECT id, url, secret, event_types, enabled, created_at FROM webhook_subscriptions
+		WHERE agency_id = $1 AND enabled AND $2 = ANY(event_types)
+		ORDER BY created_at
+	`, agencyID, eventType)
+}
+
+func (r *postgresWebhookSubscriptionRepository) query(agencyID string, query string, args ...interface{}) ([]WebhookSubscriptionEntity, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []WebhookSubscriptionEntity
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		sub.AgencyID = agencyID
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *postgresWebhookSubscriptionRepository) Get(agencyID string, id string) (*WebhookSubscriptionEntity, error) {
+	sub, err := scanWebhookSubscription(r.db.QueryRow(`
+		SELECT id, url, secret, event_types, enabled, created_at FROM webhook_subscriptions
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sub.AgencyID = agencyID
+	return sub, nil
+}
+
+func (r *postgresWebhookSubscriptionRepository) Update(sub WebhookSubscriptionEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_subscriptions SET url = $3, event_types = $4, enabled = $5
+		WHERE agency_id = $1 AND id = $2
+	`, sub.AgencyID, sub.ID, sub.URL, pq.Array(sub.EventTypes), sub.Enabled)
+	return err
+}
+
+func (r *postgresWebhookSubscriptionRepository) Delete(agencyID string, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE agency_id = $1 AND id = $2`, agencyID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanWebhookSubscription(row rowScanner) (*WebhookSubscriptionEntity, error) {
+	var sub WebhookSubscriptionEntity
+	if err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.EventTypes), &sub.Enabled, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDeliveryEntity is one event sent, or being sent, to a subscription.
+// EventID is shared by every delivery of the same event, including
+// redeliveries, so receivers can drop duplicates.
+type WebhookDeliveryEntity struct {
+	ID             string
+	SubscriptionID string
+	AgencyID       string
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+type WebhookDeliveryRepository interface {
+	Create(delivery WebhookDeliveryEntity) (*WebhookDeliveryEntity, error)
+	Get(agencyID string, id string) (*WebhookDeliveryEntity, error)
+	// List returns a subscription's most recent deliveries, newest first.
+	List(subscriptionID string, limit int) ([]WebhookDeliveryEntity, error)
+	// ClaimDue takes up to limit pending deliveries due by now and moves
+	// their next attempt to leaseUntil, so other server instances skip them
+	// while this one tries.
+	ClaimDue(now time.Time, leaseUntil time.Time, limit int) ([]WebhookDeliveryEntity, error)
+	// RecordAttempt saves the delivery's status, attempt count and outcome.
+	RecordAttempt(delivery WebhookDeliveryEntity) error
+}
+
+type postgresWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookDeliveryRepository(db *sql.DB) WebhookDeliveryRepository {
+	return &postgresWebhookDeliveryRepository{db: db}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, agency_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, delivered_at, created_at`
+
+func (r *postgresWebhookDeliveryRepository) Create(d WebhookDeliveryEntity) (*WebhookDeliveryEntity, error) {
+	d.ID = uuid.New().String()
+	d.CreatedAt = time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO webhook_deliveries (id, subscription_id, agency_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, d.ID, d.SubscriptionID, d.AgencyID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *postgresWebhookDeliveryRepository) Get(agencyID string, id string) (*WebhookDeliveryEntity, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRow(`
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE agency_id = $1 AND id = $2
+	`, agencyID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *postgresWebhookDeliveryRepository) List(subscriptionID string, limit int) ([]WebhookDeliveryEntity, error) {
+	rows, err := r.db.Query(`
+		SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+func (r *postgresWebhookDeliveryRepository) ClaimDue(now time.Time, leaseUntil time.Time, limit int) ([]WebhookDeliveryEntity, error) {
+	rows, err := r.db.Query(`
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+func (r *postgresWebhookDeliveryRepository) RecordAttempt(d WebhookDeliveryEntity) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6, delivered_at = $7
+		WHERE id = $1
+	`, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt)
+	return err
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]WebhookDeliveryEntity, error) {
+	defer rows.Close()
+	var deliveries []WebhookDeliveryEntity
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row rowScanner) (*WebhookDeliveryEntity, error) {
+	var d WebhookDeliveryEntity
+	var next, delivered sql.NullTime
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.AgencyID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&next, &d.LastStatusCode, &d.LastError, &delivered, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	if next.Valid {
+		d.NextAttemptAt = &next.Time
+	}
+	if delivered.Valid {
+		d.DeliveredAt = &delivered.Time
+	}
+	return &d, nil
+}
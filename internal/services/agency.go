@@ -1,36 +1,174 @@
 package services
 
 import (
+	"errors"
+
+	"github.com/agency-finance-reality/server/internal/clock"
 	"github.com/agency-finance-reality/server/internal/models"
 	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Agency member roles
+const (
+	RoleOwner   = "owner"
+	RoleFinance = "finance"
+	RoleViewer  = "viewer"
+)
+
+var (
+	ErrAlreadyMember           = errors.New("already a member of this agency")
+	ErrAlreadyInvited          = errors.New("already invited to this agency")
+	ErrAgencyExists            = errors.New("an agency with this name already exists")
+	ErrAgencyNotFound          = errors.New("agency not found")
+	ErrAgencySelectionRequired = errors.New("multiple agencies: select one with the X-Agency-ID header")
 )
 
 type AgencyService interface {
-	CreateAgency(userID string, name string, currency string, startingCash float64) error
-	GetAgencyByUserID(userID string) (*models.AgencyView, error)
+	CreateAgency(userID string, name string, currency string, startingCash float64) (*models.AgencyView, error)
+	ListAgencies(userID string) ([]models.AgencyView, error)
+	ResolveAgency(userID string, agencyID string) (*models.AgencyView, error)
+	ListMembers(agencyID string) ([]models.MemberView, error)
+	InviteMember(agencyID string, invitedBy string, email string, role string) (*models.InvitationView, error)
+	ListInvitations(agencyID string) ([]models.InvitationView, error)
 }
 
 type agencyService struct {
 	agencyRepo repository.AgencyRepository
+	memberRepo repository.MemberRepository
+	clock      clock.Clock
+}
+
+func NewAgencyService(agencyRepo repository.AgencyRepository, memberRepo repository.MemberRepository, clk clock.Clock) AgencyService {
+	return &agencyService{
+		agencyRepo: agencyRepo,
+		memberRepo: memberRepo,
+		clock:      clk,
+	}
+}
+
+func (s *agencyService) CreateAgency(userID string, name string, currency string, startingCash float64) (*models.AgencyView, error) {
+	// A new agency has default (UTC) settings until the owner changes them
+	openingDate := s.clock.Now().UTC().Format("2006-01-02")
+	entity, err := s.agencyRepo.Create(userID, name, currency, startingCash, openingDate)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrAgencyExists
+	} else if err != nil {
+		return nil, err
+	}
+	return toAgencyView(*entity), nil
+}
+
+func (s *agencyService) ListAgencies(userID string) ([]models.AgencyView, error) {
+	entities, err := s.agencyRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.AgencyView, len(entities))
+	for i, e := range entities {
+		views[i] = *toAgencyView(e)
+	}
+	return views, nil
+}
+
+// ResolveAgency picks the agency a request acts on. An explicit agencyID must
+// be one the user belongs to; without one, the user's only agency is used.
+func (s *agencyService) ResolveAgency(userID string, agencyID string) (*models.AgencyView, error) {
+	if agencyID != "" {
+		if _, err := uuid.Parse(agencyID); err != nil {
+			return nil, ErrAgencyNotFound
+		}
+		entity, err := s.agencyRepo.GetForUser(userID, agencyID)
+		if err != nil {
+			return nil, err
+		}
+		if entity == nil {
+			return nil, ErrAgencyNotFound
+		}
+		return toAgencyView(*entity), nil
+	}
+
+	entities, err := s.agencyRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(entities) {
+	case 0:
+		return nil, ErrAgencyNotFound
+	case 1:
+		return toAgencyView(entities[0]), nil
+	default:
+		return nil, ErrAgencySelectionRequired
+	}
 }
 
-func NewAgencyService(agencyRepo repository.AgencyRepository) AgencyService {
-	return &agencyService{agencyRepo: agencyRepo}
+func (s *agencyService) ListMembers(agencyID string) ([]models.MemberView, error) {
+	entities, err := s.memberRepo.ListMembers(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.MemberView, len(entities))
+	for i, e := range entities {
+		views[i] = models.MemberView{
+			FounderID: e.FounderID,
+			Email:     e.Email,
+			Role:      e.Role,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	return views, nil
 }
 
-func (s *agencyService) CreateAgency(userID string, name string, currency string, startingCash float64) error {
-	return s.agencyRepo.Create(userID, name, currency, startingCash)
+func (s *agencyService) InviteMember(agencyID string, invitedBy string, email string, role string) (*models.InvitationView, error) {
+	member, err := s.memberRepo.IsMemberByEmail(agencyID, email)
+	if err != nil {
+		return nil, err
+	}
+	if member {
+		return nil, ErrAlreadyMember
+	}
+
+	inv, err := s.memberRepo.CreateInvitation(agencyID, email, role, invitedBy)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrAlreadyInvited
+	} else if err != nil {
+		return nil, err
+	}
+	// New users accept on their first request; existing ones are added now
+	if err := s.memberRepo.AcceptForExistingUsers(inv.ID); err != nil {
+		return nil, err
+	}
+	return toInvitationView(*inv), nil
 }
 
-func (s *agencyService) GetAgencyByUserID(userID string) (*models.AgencyView, error) {
-	entity, err := s.agencyRepo.GetByUserID(userID)
+func (s *agencyService) ListInvitations(agencyID string) ([]models.InvitationView, error) {
+	entities, err := s.memberRepo.ListPendingInvitations(agencyID)
 	if err != nil {
 		return nil, err
 	}
+	views := make([]models.InvitationView, len(entities))
+	for i, e := range entities {
+		views[i] = *toInvitationView(e)
+	}
+	return views, nil
+}
+
+func toAgencyView(e repository.AgencyEntity) *models.AgencyView {
 	return &models.AgencyView{
-		ID:           entity.ID,
-		Name:         entity.Name,
-		BaseCurrency: entity.BaseCurrency,
-		CreatedAt:    entity.CreatedAt,
-	}, nil
+		ID:           e.ID,
+		Name:         e.Name,
+		BaseCurrency: e.BaseCurrency,
+		Role:         e.Role,
+		CreatedAt:    e.CreatedAt,
+	}
+}
+
+func toInvitationView(e repository.InvitationEntity) *models.InvitationView {
+	return &models.InvitationView{
+		ID:        e.ID,
+		Email:     e.Email,
+		Role:      e.Role,
+		InvitedBy: e.InvitedBy,
+		CreatedAt: e.CreatedAt,
+	}
 }
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/notify"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidAlertRule  = errors.New("invalid alert rule")
+)
+
+const (
+	defaultAlertCooldownMinutes = 24 * 60
+	defaultAlertHistory         = 50
+	maxAlertHistory             = 500
+	// alertRetryInterval spaces out attempts to deliver an alert whose send
+	// failed.
+	alertRetryInterval = 10 * time.Minute
+)
+
+// alertMetric is a measurement rules can watch, how it reads in a message,
+// and the bounds a rule gets when it sets none.
+type alertMetric struct {
+	label    string
+	unit     string
+	min, max *float64
+}
+
+var alertMetrics = map[string]alertMetric{
+	MeasureRunwayMonths:       {label: "Runway", unit: " months", min: bound(3)},
+	MeasureRetainerCoverage:   {label: "Retainer coverage", unit: "x", min: bound(1)},
+	MeasureTopClientPercent:   {label: "Top client share", unit: "%", max: bound(40)},
+	MeasureUtilizationPercent: {label: "Utilization", unit: "%", min: bound(60), max: bound(85)},
+	MeasureMarginPercent:      {label: "Profit margin", unit: "%", min: bound(0)},
+}
+
+func (m alertMetric) format(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64) + m.unit
+}
+
+// AlertRuleInput creates or replaces a rule. With neither Min nor Max the
+// metric's default bounds apply; a nil CooldownMinutes is one day.
+type AlertRuleInput struct {
+	Metric          string
+	Min             *float64
+	Max             *float64
+	Channel         string
+	Target          string
+	CooldownMinutes *int
+	Enabled         *bool
+}
+
+type AlertService interface {
+	ListRules(agencyID string) ([]models.AlertRuleView, error)
+	CreateRule(agencyID string, input AlertRuleInput) (*models.AlertRuleView, error)
+	UpdateRule(agencyID string, id string, input AlertRuleInput) (*models.AlertRuleView, error)
+	DeleteRule(agencyID string, id string) error
+	ListAlerts(agencyID string, limit int) ([]models.AlertView, error)
+	Evaluate(agencyID string) error
+	EvaluateAll() error
+}
+
+type alertService struct {
+	agencyRepo   repository.AgencyRepository
+	ruleRepo     repository.AlertRuleRepository
+	alertRepo    repository.AlertRepository
+	settingsRepo repository.SettingsRepository
+	metrics      metricsLoader
+	channels     map[string]notify.Channel
+	clock        clock.Clock
+}
+
+// NewAlertService delivers through channels keyed by name, such as "email"
+// and "webhook". Rules can only use the channels configured here.
+func NewAlertService(
+	agencyRepo repository.AgencyRepository,
+	ruleRepo repository.AlertRuleRepository,
+	alertRepo repository.AlertRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	financeRepo repository.FinanceRepository,
+	recurringRepo repository.RecurringCostRepository,
+	retainerRepo repository.RetainerRepository,
+	timeRepo repository.TimeEntryRepository,
+	settingsRepo repository.SettingsRepository,
+	channels map[string]notify.Channel,
+	clk clock.Clock,
+) AlertService {
+	return &alertService{
+		agencyRepo:   agencyRepo,
+		ruleRepo:     ruleRepo,
+		alertRepo:    alertRepo,
+		settingsRepo: settingsRepo,
+		metrics: metricsLoader{
+			bankRepo:     bankRepo,
+			cashRepo:     cashRepo,
+			financeRepo:  financeRepo,
+			retainerRepo: retainerRepo,
+			timeRepo:     timeRepo,
+			burn:         burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		channels: channels,
+		clock:    clk,
+	}
+}
+
+func (s *alertService) ListRules(agencyID string) ([]models.AlertRuleView, error) {
+	rules, err := s.ruleRepo.List(agencyID, false)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.AlertRuleView, len(rules))
+	for i, r := range rules {
+		views[i] = toAlertRuleView(r)
+	}
+	return views, nil
+}
+
+func (s *alertService) CreateRule(agencyID string, input AlertRuleInput) (*models.AlertRuleView, error) {
+	rule := repository.AlertRuleEntity{AgencyID: agencyID}
+	if err := s.applyRuleInput(&rule, input); err != nil {
+		return nil, err
+	}
+	created, err := s.ruleRepo.Create(rule)
+	if err != nil {
+		return nil, err
+	}
+	view := toAlertRuleView(*created)
+	return &view, nil
+}
+
+// UpdateRule replaces the rule's settings. The rule is judged afresh, so a
+// breach that is still ongoing alerts again once the cooldown allows.
+func (s *alertService) UpdateRule(agencyID string, id string, input AlertRuleInput) (*models.AlertRuleView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	rule, err := s.ruleRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	if err := s.applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(*rule); err != nil {
+		return nil, err
+	}
+	rule.BreachedSince = nil
+	view := toAlertRuleView(*rule)
+	return &view, nil
+}
+
+func (s *alertService) applyRuleInput(rule *repository.AlertRuleEntity, input AlertRuleInput) error {
+	metric, ok := alertMetrics[input.Metric]
+	if !ok {
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidAlertRule, input.Metric)
+	}
+	channel, ok := s.channels[input.Channel]
+	if !ok {
+		return fmt.Errorf("%w: %s delivery is not configured", ErrInvalidAlertRule, input.Channel)
+	}
+	if err := channel.Validate(input.Target); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAlertRule, err)
+	}
+
+	rule.Metric = input.Metric
+	rule.Min, rule.Max = input.Min, input.Max
+	if rule.Min == nil && rule.Max == nil {
+		rule.Min, rule.Max = metric.min, metric.max
+	}
+	if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+		return fmt.Errorf("%w: min is above max", ErrInvalidAlertRule)
+	}
+	rule.Channel = input.Channel
+	rule.Target = input.Target
+	rule.CooldownMinutes = defaultAlertCooldownMinutes
+	if input.CooldownMinutes != nil {
+		rule.CooldownMinutes = *input.CooldownMinutes
+	}
+	rule.Enabled = input.Enabled == nil || *input.Enabled
+	return nil
+}
+
+func (s *alertService) DeleteRule(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrAlertRuleNotFound
+	}
+	deleted, err := s.ruleRepo.Delete(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// ListAlerts returns the most recent alerts, 50 unless limit says otherwise.
+func (s *alertService) ListAlerts(agencyID string, limit int) ([]models.AlertView, error) {
+	if limit == 0 {
+		limit = defaultAlertHistory
+	}
+	if limit < 1 || limit > maxAlertHistory {
+		return nil, fmt.Errorf("%w: limit must be 1-%d", ErrInvalidDateRange, maxAlertHistory)
+	}
+	alerts, err := s.alertRepo.List(agencyID, limit)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.AlertView, len(alerts))
+	for i, a := range alerts {
+		views[i] = toAlertView(a)
+	}
+	return views, nil
+}
+
+// EvaluateAll checks every agency's rules. It runs from the scheduler to
+// catch changes no write announces, such as the lookback window moving on.
+func (s *alertService) EvaluateAll() error {
+	ids, err := s.agencyRepo.ListIDs()
+	if err != nil {
+		return err
+	}
+	var failed []error
+	for _, id := range ids {
+		if err := s.Evaluate(id); err != nil {
+			failed = append(failed, fmt.Errorf("agency %s: %w", id, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// Evaluate checks the agency's enabled rules against its current
+// measurements. A rule alerts when its measurement goes out of bounds, then
+// stays quiet until the value is back within them; it never alerts twice
+// within its cooldown. An alert that couldn't be delivered is sent again while
+// the breach lasts, rather than fired again. Concurrent evaluations, from
+// other writes or server instances, can't both send the same alert.
+func (s *alertService) Evaluate(agencyID string) error {
+	rules, err := s.ruleRepo.List(agencyID, true)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	in, err := s.metrics.load(ac, agencyID)
+	if err != nil {
+		return err
+	}
+	m := in.measurements()
+
+	var failed []error
+	for _, rule := range rules {
+		value := m.value(rule.Metric)
+		// Zero utilization means no time has been tracked, not an idle team
+		if value == nil || (rule.Metric == MeasureUtilizationPercent && in.usedHours == 0) {
+			continue
+		}
+
+		if !outOfBounds(*value, rule.Min, rule.Max) {
+			if rule.BreachedSince != nil {
+				if err := s.ruleRepo.ClearBreach(rule.ID); err != nil {
+					failed = append(failed, err)
+				}
+			}
+			continue
+		}
+
+		if rule.BreachedSince != nil {
+			if err := s.retry(rule); err != nil {
+				failed = append(failed, fmt.Errorf("rule %s: %w", rule.ID, err))
+			}
+			continue
+		}
+
+		now := s.clock.Now()
+		claimed, err := s.ruleRepo.ClaimFiring(rule.ID, now)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.fire(rule, *value, now); err != nil {
+			failed = append(failed, fmt.Errorf("rule %s: %w", rule.ID, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+func outOfBounds(v float64, min *float64, max *float64) bool {
+	return (min != nil && v < *min) || (max != nil && v > *max)
+}
+
+// fire records the alert the rule was claimed for at firedAt and sends it.
+// Once the alert is recorded, a failed send is retried by later evaluations;
+// if it can't be recorded, the claim is given back so the next evaluation
+// fires instead.
+func (s *alertService) fire(rule repository.AlertRuleEntity, value float64, firedAt time.Time) error {
+	ruleID := rule.ID
+	alert, err := s.alertRepo.Create(repository.AlertEntity{
+		AgencyID: rule.AgencyID,
+		RuleID:   &ruleID,
+		Metric:   rule.Metric,
+		Value:    value,
+		Min:      rule.Min,
+		Max:      rule.Max,
+		Channel:  rule.Channel,
+		Target:   rule.Target,
+		FiredAt:  firedAt,
+	}, firedAt.Add(alertRetryInterval))
+	if err != nil {
+		if releaseErr := s.ruleRepo.ReleaseClaim(rule.ID, firedAt, rule.LastFiredAt); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	return s.deliver(rule, *alert)
+}
+
+// retry sends the alert for the rule's current breach again if its delivery
+// failed and a retry is due.
+func (s *alertService) retry(rule repository.AlertRuleEntity) error {
+	alert, err := s.alertRepo.LatestUndelivered(rule.ID, *rule.BreachedSince)
+	if err != nil || alert == nil {
+		return err
+	}
+	now := s.clock.Now()
+	claimed, err := s.alertRepo.ClaimRetry(alert.ID, now, now.Add(alertRetryInterval))
+	if err != nil || !claimed {
+		return err
+	}
+	return s.deliver(rule, *alert)
+}
+
+// deliver sends the alert and keeps the outcome on it. A failed send is also
+// returned; a channel that isn't configured only counts as a failed delivery.
+func (s *alertService) deliver(rule repository.AlertRuleEntity, alert repository.AlertEntity) error {
+	agency, err := s.agencyRepo.GetByID(alert.AgencyID)
+	if err != nil {
+		return err
+	}
+
+	view := toAlertView(alert)
+	msg := notify.Message{
+		Subject: fmt.Sprintf("%s: %s", agency.Name, view.Summary),
+		Body: fmt.Sprintf("%s.\n\nYou won't be alerted about this again until it is back within bounds, and not within %d minutes of this alert.\n",
+			view.Summary, rule.CooldownMinutes),
+		Payload: models.AlertNotificationView{
+			AgencyID:   agency.ID,
+			AgencyName: agency.Name,
+			Alert:      view,
+		},
+	}
+
+	channel, ok := s.channels[alert.Channel]
+	if !ok {
+		reason := alert.Channel + " delivery is not configured"
+		return s.alertRepo.RecordDelivery(alert.ID, s.clock.Now(), &reason)
+	}
+	sendErr := channel.Send(alert.Target, msg)
+	if sendErr == nil {
+		return s.alertRepo.RecordDelivery(alert.ID, s.clock.Now(), nil)
+	}
+	reason := sendErr.Error()
+	if err := s.alertRepo.RecordDelivery(alert.ID, s.clock.Now(), &reason); err != nil {
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
+
+func toAlertRuleView(r repository.AlertRuleEntity) models.AlertRuleView {
+	return models.AlertRuleView{
+		ID:              r.ID,
+		Metric:          r.Metric,
+		Min:             r.Min,
+		Max:             r.Max,
+		Channel:         r.Channel,
+		Target:          r.Target,
+		CooldownMinutes: r.CooldownMinutes,
+		Enabled:         r.Enabled,
+		Breached:        r.BreachedSince != nil,
+		LastFiredAt:     r.LastFiredAt,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+func toAlertView(a repository.AlertEntity) models.AlertView {
+	view := models.AlertView{
+		ID:            a.ID,
+		RuleID:        a.RuleID,
+		Metric:        a.Metric,
+		Value:         a.Value,
+		Min:           a.Min,
+		Max:           a.Max,
+		Channel:       a.Channel,
+		Target:        a.Target,
+		FiredAt:       a.FiredAt,
+		DeliveredAt:   a.DeliveredAt,
+		DeliveryError: a.DeliveryError,
+	}
+	metric := alertMetrics[a.Metric]
+	if a.Min != nil && a.Value < *a.Min {
+		view.Summary = fmt.Sprintf("%s is %s, below %s", metric.label, metric.format(a.Value), metric.format(*a.Min))
+	} else if a.Max != nil {
+		view.Summary = fmt.Sprintf("%s is %s, above %s", metric.label, metric.format(a.Value), metric.format(*a.Max))
+	}
+	return view
+}
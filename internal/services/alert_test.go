@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agency-finance-reality/server/internal/notify"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+func TestFailedAlertDeliveryIsRetriedNotRefired(t *testing.T) {
+	ruleRepo := &fakeAlertRuleRepo{rule: repository.AlertRuleEntity{
+		ID:              "rule-1",
+		AgencyID:        "agency-1",
+		Metric:          MeasureRunwayMonths,
+		Min:             bound(3),
+		Channel:         "email",
+		Target:          "founder@example.com",
+		CooldownMinutes: 0,
+		Enabled:         true,
+	}}
+	alertRepo := &fakeAlertRepo{}
+	channel := &fakeChannel{err: errors.New("relay unavailable")}
+
+	// 10000 cash against 5000 a month of rent entered by hand: two months of runway
+	evaluate := func(now string) error {
+		svc := NewAlertService(
+			&fakeAgencyRepo{},
+			ruleRepo,
+			alertRepo,
+			&fakeBankRepo{balances: []repository.AccountBalanceEntity{{AccountID: "account-1", BaseBalance: 10000}}},
+			nil,
+			&fakeFinanceRepo{oneOffFixed: 15000},
+			&fakeRecurringCostRepo{},
+			&fakeRetainerRepo{},
+			&fakeTimeRepo{},
+			&fakeSettingsRepo{settings: repository.DefaultAgencySettings("")},
+			map[string]notify.Channel{"email": channel},
+			fixedAt(t, now),
+		)
+		return svc.Evaluate("agency-1")
+	}
+
+	if err := evaluate("2026-10-18T09:00:00Z"); err == nil {
+		t.Error("failed send was not reported")
+	}
+	// Before the retry is due, and again once it is but the relay is still down
+	evaluate("2026-10-18T09:05:00Z")
+	evaluate("2026-10-18T09:11:00Z")
+	if err := evaluate("2026-10-18T09:15:00Z"); err != nil {
+		t.Errorf("retry not yet due: %v", err)
+	}
+
+	channel.err = nil
+	if err := evaluate("2026-10-18T09:22:00Z"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if err := evaluate("2026-10-18T09:40:00Z"); err != nil {
+		t.Fatalf("after delivery: %v", err)
+	}
+
+	if len(alertRepo.alerts) != 1 {
+		t.Fatalf("recorded %d alerts for one breach, want 1", len(alertRepo.alerts))
+	}
+	if channel.sends != 3 {
+		t.Errorf("sent %d times, want 3", channel.sends)
+	}
+	if alertRepo.alerts[0].DeliveredAt == nil || alertRepo.alerts[0].DeliveryError != nil {
+		t.Errorf("alert not marked delivered: %+v", alertRepo.alerts[0])
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/auth"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTokenNotFound = errors.New("api token not found")
+	ErrInvalidToken  = errors.New("invalid api token")
+)
+
+type APITokenService interface {
+	CreateToken(founderID string, name string, scope string, expiresAt *time.Time) (*models.CreatedAPITokenView, error)
+	ListTokens(founderID string) ([]models.APITokenView, error)
+	RevokeToken(founderID string, tokenID string) error
+	ResolveToken(token string) (*auth.Identity, error)
+}
+
+type apiTokenService struct {
+	tokenRepo repository.APITokenRepository
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository) APITokenService {
+	return &apiTokenService{tokenRepo: tokenRepo}
+}
+
+func (s *apiTokenService) CreateToken(founderID string, name string, scope string, expiresAt *time.Time) (*models.CreatedAPITokenView, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidToken)
+	}
+
+	token, err := auth.GenerateAPIToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep enough of the token to recognise it in listings without making it usable
+	prefix := token[:len(auth.APITokenPrefix)+6]
+	entity, err := s.tokenRepo.Create(founderID, name, prefix, auth.HashAPIToken(token), scope, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPITokenView{
+		APITokenView: toAPITokenView(*entity),
+		Token:        token,
+	}, nil
+}
+
+func (s *apiTokenService) ListTokens(founderID string) ([]models.APITokenView, error) {
+	entities, err := s.tokenRepo.ListActive(founderID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.APITokenView, len(entities))
+	for i, e := range entities {
+		views[i] = toAPITokenView(e)
+	}
+	return views, nil
+}
+
+func (s *apiTokenService) RevokeToken(founderID string, tokenID string) error {
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return ErrTokenNotFound
+	}
+	revoked, err := s.tokenRepo.Revoke(founderID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+func (s *apiTokenService) ResolveToken(token string) (*auth.Identity, error) {
+	entity, err := s.tokenRepo.GetActiveByHash(auth.HashAPIToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, errors.New("invalid api token")
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(entity.ID); err != nil {
+		return nil, err
+	}
+
+	return &auth.Identity{
+		Subject:    entity.FounderID,
+		Email:      entity.FounderEmail,
+		Scope:      entity.Scope,
+		APITokenID: entity.ID,
+	}, nil
+}
+
+func toAPITokenView(e repository.APITokenEntity) models.APITokenView {
+	return models.APITokenView{
+		ID:         e.ID,
+		Name:       e.Name,
+		Prefix:     e.Prefix,
+		Scope:      e.Scope,
+		ExpiresAt:  e.ExpiresAt,
+		LastUsedAt: e.LastUsedAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
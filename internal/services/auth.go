@@ -10,12 +10,23 @@ type AuthService interface {
 
 type authService struct {
 	founderRepo repository.FounderRepository
+	memberRepo  repository.MemberRepository
 }
 
-func NewAuthService(founderRepo repository.FounderRepository) AuthService {
-	return &authService{founderRepo: founderRepo}
+func NewAuthService(founderRepo repository.FounderRepository, memberRepo repository.MemberRepository) AuthService {
+	return &authService{
+		founderRepo: founderRepo,
+		memberRepo:  memberRepo,
+	}
 }
 
+// EnsureUser provisions the founder on their first request and accepts any
+// pending agency invitations addressed to their email. Invitations to users
+// who already exist are accepted when they're sent.
 func (s *authService) EnsureUser(id string, email string) error {
-	return s.founderRepo.EnsureUser(id, email)
+	created, err := s.founderRepo.EnsureUser(id, email)
+	if err != nil || !created {
+		return err
+	}
+	return s.memberRepo.AcceptInvitations(id, email)
 }
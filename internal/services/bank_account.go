@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAccountNotFound     = errors.New("bank account not found")
+	ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+	// ErrCashTrackedByAccounts rejects agency-wide cash snapshots once bank
+	// accounts are in use; their consolidated total is the only source.
+	ErrCashTrackedByAccounts = errors.New("cash is recorded per bank account")
+)
+
+// BankAccountUpdate is a partial update; nil fields are left unchanged.
+type BankAccountUpdate struct {
+	Name       *string
+	Restricted *bool
+}
+
+type BankAccountService interface {
+	ListAccounts(agencyID string) ([]models.BankAccountView, error)
+	CreateAccount(agencyID string, name string, currency string, restricted bool) (*models.BankAccountView, error)
+	UpdateAccount(agencyID string, id string, update BankAccountUpdate) (*models.BankAccountView, error)
+	ArchiveAccount(agencyID string, userID string, id string) error
+	RecordAccountSnapshot(agencyID string, userID string, accountID string, date string, balance float64, exchangeRate *float64) (*models.BankAccountSnapshotView, error)
+}
+
+type bankAccountService struct {
+	agencyRepo   repository.AgencyRepository
+	bankRepo     repository.BankAccountRepository
+	cashRepo     repository.CashSnapshotRepository
+	settingsRepo repository.SettingsRepository
+	events       publisher
+	clock        clock.Clock
+}
+
+func NewBankAccountService(
+	agencyRepo repository.AgencyRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	settingsRepo repository.SettingsRepository,
+	bus *events.Bus,
+	clk clock.Clock,
+) BankAccountService {
+	return &bankAccountService{
+		agencyRepo:   agencyRepo,
+		bankRepo:     bankRepo,
+		cashRepo:     cashRepo,
+		settingsRepo: settingsRepo,
+		events:       publisher{bus: bus, clock: clk},
+		clock:        clk,
+	}
+}
+
+func (s *bankAccountService) ListAccounts(agencyID string) ([]models.BankAccountView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.bankRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.bankRepo.LatestBalances(agencyID, ac.today())
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]repository.AccountBalanceEntity, len(balances))
+	for _, b := range balances {
+		latest[b.AccountID] = b
+	}
+
+	views := make([]models.BankAccountView, len(accounts))
+	for i, a := range accounts {
+		views[i] = toBankAccountView(a)
+		if b, ok := latest[a.ID]; ok {
+			views[i].LatestDate = &b.Date
+			views[i].LatestBalance = &b.Balance
+			views[i].ExchangeRate = &b.ExchangeRate
+			views[i].BaseBalance = &b.BaseBalance
+		}
+	}
+	return views, nil
+}
+
+func (s *bankAccountService) CreateAccount(agencyID string, name string, currency string, restricted bool) (*models.BankAccountView, error) {
+	account, err := s.bankRepo.Create(agencyID, name, strings.ToUpper(currency), restricted)
+	if err != nil {
+		return nil, err
+	}
+	view := toBankAccountView(*account)
+	return &view, nil
+}
+
+func (s *bankAccountService) UpdateAccount(agencyID string, id string, update BankAccountUpdate) (*models.BankAccountView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAccountNotFound
+	}
+	account, err := s.bankRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if update.Name != nil {
+		account.Name = *update.Name
+	}
+	if update.Restricted != nil {
+		account.Restricted = *update.Restricted
+	}
+	if err := s.bankRepo.Update(*account); err != nil {
+		return nil, err
+	}
+	view := toBankAccountView(*account)
+	return &view, nil
+}
+
+// ArchiveAccount hides the account and drops it from consolidated balances
+// from today on. Its snapshots, and earlier consolidated totals, are kept.
+func (s *bankAccountService) ArchiveAccount(agencyID string, userID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrAccountNotFound
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	archived, err := s.bankRepo.Archive(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !archived {
+		return ErrAccountNotFound
+	}
+	_, err = s.consolidate(agencyID, userID, ac.today())
+	return err
+}
+
+// RecordAccountSnapshot sets an account's balance for date (today when empty)
+// and rewrites the agency-wide snapshot for that date and any later recorded
+// dates, so cash history and metrics see the consolidated total. Accounts held
+// in a currency other than the agency's base currency need an exchange rate.
+func (s *bankAccountService) RecordAccountSnapshot(agencyID string, userID string, accountID string, date string, balance float64, exchangeRate *float64) (*models.BankAccountSnapshotView, error) {
+	if _, err := uuid.Parse(accountID); err != nil {
+		return nil, ErrAccountNotFound
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	entryDate, err := ac.entryDate(date, agency.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.bankRepo.Get(agencyID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	rate := 1.0
+	if account.Currency != agency.BaseCurrency {
+		if exchangeRate == nil {
+			return nil, fmt.Errorf("%w: %s account needs an exchange rate to %s", ErrInvalidExchangeRate, account.Currency, agency.BaseCurrency)
+		}
+		rate = *exchangeRate
+	} else if exchangeRate != nil && *exchangeRate != 1 {
+		return nil, fmt.Errorf("%w: account is already in %s", ErrInvalidExchangeRate, agency.BaseCurrency)
+	}
+	if rate <= 0 {
+		return nil, fmt.Errorf("%w: must be positive", ErrInvalidExchangeRate)
+	}
+
+	if err := s.bankRepo.UpsertSnapshot(agencyID, accountID, entryDate, balance, rate); err != nil {
+		return nil, err
+	}
+
+	consolidated, err := s.consolidate(agencyID, userID, entryDate)
+	if err != nil {
+		return nil, err
+	}
+	later, err := s.cashRepo.ListInRange(agencyID, entryDate, ac.today())
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range later {
+		if snap.Date == entryDate {
+			continue
+		}
+		if _, err := s.consolidate(agencyID, userID, snap.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	view := &models.BankAccountSnapshotView{
+		AccountID:           accountID,
+		Date:                entryDate,
+		Balance:             balance,
+		ExchangeRate:        rate,
+		BaseBalance:         balance * rate,
+		ConsolidatedBalance: consolidated,
+	}
+	s.events.publish(events.BankSnapshotRecorded, agencyID, view)
+	return view, nil
+}
+
+// consolidate writes the sum of every account's latest balance as of date to
+// the agency-wide snapshot.
+func (s *bankAccountService) consolidate(agencyID string, userID string, date string) (float64, error) {
+	balances, err := s.bankRepo.LatestBalances(agencyID, date)
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, b := range balances {
+		total += b.BaseBalance
+	}
+	total = math.Round(total*100) / 100
+	return total, s.cashRepo.Upsert(agencyID, date, total, userID)
+}
+
+// cashPosition is the agency's current cash split by restriction. Agencies
+// without bank accounts fall back to the latest agency-wide snapshot, which is
+// treated as unrestricted.
+type cashPosition struct {
+	total      float64
+	restricted float64
+}
+
+func (p cashPosition) available() float64 {
+	return p.total - p.restricted
+}
+
+func currentCashPosition(bankRepo repository.BankAccountRepository, cashRepo repository.CashSnapshotRepository, agencyID string, today string) (*cashPosition, error) {
+	balances, err := bankRepo.LatestBalances(agencyID, today)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) > 0 {
+		var p cashPosition
+		for _, b := range balances {
+			p.total += b.BaseBalance
+			if b.Restricted {
+				p.restricted += b.BaseBalance
+			}
+		}
+		return &p, nil
+	}
+
+	cash, err := cashRepo.GetLatest(agencyID)
+	if err != nil || cash == nil {
+		return nil, err
+	}
+	return &cashPosition{total: *cash}, nil
+}
+
+func toBankAccountView(a repository.BankAccountEntity) models.BankAccountView {
+	return models.BankAccountView{
+		ID:         a.ID,
+		Name:       a.Name,
+		Currency:   a.Currency,
+		Restricted: a.Restricted,
+	}
+}
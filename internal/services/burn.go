@@ -0,0 +1,83 @@
+package services
+
+import (
+	"math"
+
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+// burnFigures splits monthly burn into what the agency is committed to and
+// what it has recently spent on top of that.
+type burnFigures struct {
+	// recurring is the monthly equivalent of recurring templates active today
+	recurring float64
+	// oneOffAverage is non-recurring fixed spend averaged over the trailing months
+	oneOffAverage float64
+	// variableAverage is non-recurring variable spend over the same months.
+	// Burn leaves it out, but cash forecasts still have to pay it.
+	variableAverage float64
+	months          int
+	// raw is every fixed cost posted in the lookback window, the old burn figure
+	raw float64
+}
+
+func (b burnFigures) monthly() float64 {
+	return math.Round((b.recurring+b.oneOffAverage)*100) / 100
+}
+
+// burnCalculator computes normalized monthly burn. Recurring costs count at
+// their amortized monthly rate regardless of when they were last posted, so an
+// annual bill or a rent payment just outside the window doesn't swing runway.
+type burnCalculator struct {
+	financeRepo   repository.FinanceRepository
+	recurringRepo repository.RecurringCostRepository
+}
+
+func (c burnCalculator) compute(ac *agencyContext, agencyID string) (*burnFigures, error) {
+	today := ac.today()
+	figures := &burnFigures{months: ac.settings.BurnAverageMonths}
+	if figures.months < 1 {
+		figures.months = 1
+	}
+
+	templates, err := c.recurringRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.StartDate > today || (t.EndDate != nil && *t.EndDate < today) {
+			continue
+		}
+		figures.recurring += monthlyEquivalent(t)
+	}
+
+	from := ac.now.AddDate(0, -figures.months, 1).Format("2006-01-02")
+	oneOff, err := c.financeRepo.SumOneOffFixedCostsBetween(agencyID, from, today)
+	if err != nil {
+		return nil, err
+	}
+	figures.oneOffAverage = oneOff / float64(figures.months)
+	variable, err := c.financeRepo.SumOneOffVariableCostsBetween(agencyID, from, today)
+	if err != nil {
+		return nil, err
+	}
+	figures.variableAverage = variable / float64(figures.months)
+
+	figures.raw, err = c.financeRepo.SumFixedCostsInRange(agencyID, ac.windowStart())
+	if err != nil {
+		return nil, err
+	}
+
+	return figures, nil
+}
+
+func monthlyEquivalent(t repository.RecurringCostEntity) float64 {
+	switch t.Cadence {
+	case "weekly":
+		return t.Amount * 52 / 12
+	case "annual":
+		return t.Amount / 12
+	default:
+		return t.Amount
+	}
+}
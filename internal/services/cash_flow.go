@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+var ErrInvalidCashFlowWeek = errors.New("invalid cash flow week")
+
+// cashFlowWeeks is the length of the rolling cash flow report.
+const cashFlowWeeks = 13
+
+// CashFlowActual is what happened in a week, as recorded by the agency.
+type CashFlowActual struct {
+	Inflows        float64
+	Outflows       float64
+	ClosingBalance float64
+}
+
+type CashFlowService interface {
+	GetThirteenWeekReport(agencyID string) (*models.CashFlowReportView, error)
+	RecordWeekActual(agencyID string, userID string, weekStart string, actual CashFlowActual) error
+	FreezeForecasts() error
+}
+
+type cashFlowService struct {
+	agencyRepo   repository.AgencyRepository
+	bankRepo     repository.BankAccountRepository
+	cashRepo     repository.CashSnapshotRepository
+	weekRepo     repository.CashFlowWeekRepository
+	settingsRepo repository.SettingsRepository
+	inputs       forecastLoader
+	clock        clock.Clock
+}
+
+func NewCashFlowService(
+	agencyRepo repository.AgencyRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	weekRepo repository.CashFlowWeekRepository,
+	financeRepo repository.FinanceRepository,
+	retainerRepo repository.RetainerRepository,
+	recurringRepo repository.RecurringCostRepository,
+	plannedRepo repository.PlannedItemRepository,
+	invoiceRepo repository.InvoiceRepository,
+	settingsRepo repository.SettingsRepository,
+	clk clock.Clock,
+) CashFlowService {
+	return &cashFlowService{
+		agencyRepo:   agencyRepo,
+		bankRepo:     bankRepo,
+		cashRepo:     cashRepo,
+		weekRepo:     weekRepo,
+		settingsRepo: settingsRepo,
+		inputs: forecastLoader{
+			retainerRepo:  retainerRepo,
+			recurringRepo: recurringRepo,
+			plannedRepo:   plannedRepo,
+			invoiceRepo:   invoiceRepo,
+			burn:          burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		clock: clk,
+	}
+}
+
+// GetThirteenWeekReport projects the next 13 full Monday-Sunday weeks from
+// the current cash position, using the same inputs as the cash forecast.
+// Movements between tomorrow and the first Monday roll into week 1's opening
+// balance. Variance compares recorded actuals with the forecasts frozen by
+// FreezeForecasts.
+func (s *cashFlowService) GetThirteenWeekReport(agencyID string) (*models.CashFlowReportView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	position, weeks, err := s.project(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range weeks {
+		roundCashFlowWeek(&weeks[i])
+	}
+
+	first, _ := time.Parse("2006-01-02", weeks[0].WeekStart)
+	recorded, err := s.weekRepo.ListWithActuals(agencyID, first.AddDate(0, 0, -7*cashFlowWeeks).Format("2006-01-02"), ac.today())
+	if err != nil {
+		return nil, err
+	}
+	variance := make([]models.CashFlowVarianceView, len(recorded))
+	for i, w := range recorded {
+		variance[i] = toCashFlowVarianceView(w)
+	}
+
+	return &models.CashFlowReportView{
+		AsOf:           ac.today(),
+		CurrentBalance: roundCents(position.total),
+		Weeks:          weeks,
+		Variance:       variance,
+	}, nil
+}
+
+// FreezeForecasts stores each agency's forecast for the coming week on the
+// Sunday before it starts, in the agency's timezone, so actuals are compared
+// against the last forecast before the week began. It runs from the
+// scheduler; only the first run each Sunday writes.
+func (s *cashFlowService) FreezeForecasts() error {
+	ids, err := s.agencyRepo.ListIDs()
+	if err != nil {
+		return err
+	}
+	var failed []error
+	for _, id := range ids {
+		if err := s.freezeForecast(id); err != nil {
+			failed = append(failed, fmt.Errorf("agency %s: %w", id, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+func (s *cashFlowService) freezeForecast(agencyID string) error {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	if ac.now.Weekday() != time.Sunday {
+		return nil
+	}
+	_, weeks, err := s.project(ac, agencyID)
+	if errors.Is(err, ErrNoCashSnapshot) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	w := weeks[0]
+	_, err = s.weekRepo.FreezeForecast(repository.CashFlowWeekEntity{
+		AgencyID:         agencyID,
+		WeekStart:        w.WeekStart,
+		ForecastInflows:  &w.Inflows.Total,
+		ForecastOutflows: &w.Outflows.Total,
+		ForecastClosing:  &w.ClosingBalance,
+	})
+	return err
+}
+
+// project computes the unrounded weekly figures for the report.
+func (s *cashFlowService) project(ac *agencyContext, agencyID string) (*cashPosition, []models.CashFlowWeekView, error) {
+	position, err := currentCashPosition(s.bankRepo, s.cashRepo, agencyID, ac.today())
+	if err != nil {
+		return nil, nil, err
+	}
+	if position == nil {
+		return nil, nil, ErrNoCashSnapshot
+	}
+
+	today, _ := time.Parse("2006-01-02", ac.today())
+	tomorrow := today.AddDate(0, 0, 1)
+	first := nextMonday(today)
+	end := first.AddDate(0, 0, 7*cashFlowWeeks-1)
+
+	in, err := s.inputs.load(ac, agencyID, tomorrow, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	weeks := make([]models.CashFlowWeekView, cashFlowWeeks)
+	for i := range weeks {
+		start := first.AddDate(0, 0, 7*i)
+		weeks[i].Week = i + 1
+		weeks[i].WeekStart = start.Format("2006-01-02")
+		weeks[i].WeekEnd = start.AddDate(0, 0, 6).Format("2006-01-02")
+	}
+
+	balance := position.total
+	firstDate := first.Format("2006-01-02")
+	for _, m := range in.movements(tomorrow, end) {
+		if m.date < firstDate {
+			balance += m.amount
+			continue
+		}
+		d, _ := time.Parse("2006-01-02", m.date)
+		w := &weeks[int(d.Sub(first).Hours()/24)/7]
+		if m.amount > 0 {
+			addInflow(&w.Inflows, m.kind, m.amount)
+		} else {
+			addOutflow(&w.Outflows, m.kind, -m.amount)
+		}
+	}
+
+	for i := range weeks {
+		w := &weeks[i]
+		w.OpeningBalance = balance
+		w.NetCashFlow = w.Inflows.Total - w.Outflows.Total
+		balance += w.NetCashFlow
+		w.ClosingBalance = balance
+	}
+	return position, weeks, nil
+}
+
+// RecordWeekActual stores what happened in the week starting weekStart, a
+// Monday. The week must have started.
+func (s *cashFlowService) RecordWeekActual(agencyID string, userID string, weekStart string, actual CashFlowActual) error {
+	start, err := time.Parse("2006-01-02", weekStart)
+	if err != nil || start.Weekday() != time.Monday {
+		return fmt.Errorf("%w: week_start must be a Monday", ErrInvalidCashFlowWeek)
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	if weekStart > ac.today() {
+		return fmt.Errorf("%w: week has not started yet", ErrInvalidCashFlowWeek)
+	}
+	return s.weekRepo.RecordActual(agencyID, weekStart, actual.Inflows, actual.Outflows, actual.ClosingBalance, userID)
+}
+
+// nextMonday returns the first Monday after t.
+func nextMonday(t time.Time) time.Time {
+	days := (int(time.Monday) - int(t.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return t.AddDate(0, 0, days)
+}
+
+func addInflow(in *models.CashFlowInflowsView, source string, amount float64) {
+	switch source {
+	case sourceRetainers:
+		in.Retainers += amount
+	case sourceInvoices:
+		in.Invoices += amount
+	default:
+		in.Planned += amount
+	}
+	in.Total += amount
+}
+
+func addOutflow(out *models.CashFlowOutflowsView, category string, amount float64) {
+	switch category {
+	case "people":
+		out.People += amount
+	case "tools":
+		out.Tools += amount
+	default:
+		out.Other += amount
+	}
+	out.Total += amount
+}
+
+func roundCashFlowWeek(w *models.CashFlowWeekView) {
+	w.OpeningBalance = roundCents(w.OpeningBalance)
+	w.Inflows.Retainers = roundCents(w.Inflows.Retainers)
+	w.Inflows.Invoices = roundCents(w.Inflows.Invoices)
+	w.Inflows.Planned = roundCents(w.Inflows.Planned)
+	w.Inflows.Total = roundCents(w.Inflows.Total)
+	w.Outflows.People = roundCents(w.Outflows.People)
+	w.Outflows.Tools = roundCents(w.Outflows.Tools)
+	w.Outflows.Other = roundCents(w.Outflows.Other)
+	w.Outflows.Total = roundCents(w.Outflows.Total)
+	w.NetCashFlow = roundCents(w.NetCashFlow)
+	w.ClosingBalance = roundCents(w.ClosingBalance)
+}
+
+func toCashFlowVarianceView(w repository.CashFlowWeekEntity) models.CashFlowVarianceView {
+	start, _ := time.Parse("2006-01-02", w.WeekStart)
+	view := models.CashFlowVarianceView{
+		WeekStart:      w.WeekStart,
+		WeekEnd:        start.AddDate(0, 0, 6).Format("2006-01-02"),
+		ActualInflows:  *w.ActualInflows,
+		ActualOutflows: *w.ActualOutflows,
+		ActualClosing:  *w.ActualClosing,
+	}
+	view.ForecastInflows, view.InflowVariance = compareForecast(w.ForecastInflows, view.ActualInflows)
+	view.ForecastOutflows, view.OutflowVariance = compareForecast(w.ForecastOutflows, view.ActualOutflows)
+	view.ForecastClosing, view.ClosingVariance = compareForecast(w.ForecastClosing, view.ActualClosing)
+	return view
+}
+
+// compareForecast returns the rounded forecast and actual minus forecast, or
+// nils when there was no forecast.
+func compareForecast(forecast *float64, actual float64) (*float64, *float64) {
+	if forecast == nil {
+		return nil, nil
+	}
+	f := roundCents(*forecast)
+	v := roundCents(actual - *forecast)
+	return &f, &v
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/models"
+)
+
+// maxHistoryDays bounds a single history request (about ten years).
+const maxHistoryDays = 3660
+
+// GetCashHistory returns the cash balance between from and to grouped by
+// interval (day, week, month, quarter or year). Quarters and years follow the
+// agency's fiscal year. Days without a snapshot carry the last known balance
+// forward, including one recorded before the range.
+func (s *financeService) GetCashHistory(agencyID string, from string, to string, interval string) (*models.CashHistoryView, error) {
+	if interval == "" {
+		interval = "day"
+	}
+	switch interval {
+	case "day", "week", "month", "quarter", "year":
+	default:
+		return nil, fmt.Errorf("%w: interval must be day, week, month, quarter or year", ErrInvalidDateRange)
+	}
+
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err = ac.dateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	if end.Sub(start) > maxHistoryDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: range is limited to %d days", ErrInvalidDateRange, maxHistoryDays)
+	}
+
+	carried, err := s.cashRepo.GetLatestBefore(agencyID, from)
+	if err != nil {
+		return nil, err
+	}
+	snaps, err := s.cashRepo.ListInRange(agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[string]float64, len(snaps))
+	for _, snap := range snaps {
+		recorded[snap.Date] = snap.CashBalance
+	}
+
+	view := &models.CashHistoryView{
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Points:   []models.CashHistoryPoint{},
+	}
+
+	var bucket *cashBucket
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		balance, ok := recorded[date]
+		if ok {
+			carried = &balance
+		}
+
+		periodStart := bucketStart(d, interval, time.Month(ac.settings.FiscalYearStartMonth))
+		if bucket == nil || !bucket.start.Equal(periodStart) {
+			if bucket != nil {
+				view.Points = append(view.Points, bucket.point())
+			}
+			bucket = &cashBucket{start: periodStart}
+		}
+		bucket.add(d, carried, ok)
+	}
+	if bucket != nil {
+		view.Points = append(view.Points, bucket.point())
+	}
+
+	// Period-over-period change in closing balance
+	for i := 1; i < len(view.Points); i++ {
+		prev, cur := view.Points[i-1].Close, view.Points[i].Close
+		if prev == nil || cur == nil {
+			continue
+		}
+		delta := *cur - *prev
+		view.Points[i].Delta = &delta
+		if *prev != 0 {
+			pct := math.Round(delta / *prev * 1000) / 10
+			view.Points[i].DeltaPercent = &pct
+		}
+	}
+
+	return view, nil
+}
+
+func bucketStart(d time.Time, interval string, fiscalStart time.Month) time.Time {
+	// Months since the fiscal year began
+	offset := (int(d.Month()) - int(fiscalStart) + 12) % 12
+	switch interval {
+	case "week":
+		// ISO weeks start on Monday
+		offset := (int(d.Weekday()) + 6) % 7
+		return d.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, d.Location())
+	case "quarter":
+		return time.Date(d.Year(), d.Month()-time.Month(offset%3), 1, 0, 0, 0, 0, d.Location())
+	case "year":
+		return time.Date(d.Year(), d.Month()-time.Month(offset), 1, 0, 0, 0, 0, d.Location())
+	default:
+		return d
+	}
+}
+
+type cashBucket struct {
+	start    time.Time
+	end      time.Time
+	open     *float64
+	close    *float64
+	min      *float64
+	max      *float64
+	sum      float64
+	count    int
+	recorded int
+}
+
+func (b *cashBucket) add(d time.Time, balance *float64, recorded bool) {
+	b.end = d
+	if recorded {
+		b.recorded++
+	}
+	if balance == nil {
+		return
+	}
+	v := *balance
+	if b.open == nil {
+		b.open = &v
+	}
+	b.close = &v
+	if b.min == nil || v < *b.min {
+		b.min = &v
+	}
+	if b.max == nil || v > *b.max {
+		b.max = &v
+	}
+	b.sum += v
+	b.count++
+}
+
+func (b *cashBucket) point() models.CashHistoryPoint {
+	p := models.CashHistoryPoint{
+		PeriodStart:  b.start.Format("2006-01-02"),
+		PeriodEnd:    b.end.Format("2006-01-02"),
+		Open:         b.open,
+		Close:        b.close,
+		Min:          b.min,
+		Max:          b.max,
+		RecordedDays: b.recorded,
+	}
+	if b.count > 0 {
+		avg := roundCents(b.sum / float64(b.count))
+		p.Avg = &avg
+	}
+	return p
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalPeriodsFollowFiscalYearStart(t *testing.T) {
+	tests := []struct {
+		date        string
+		fiscalStart time.Month
+		interval    string
+		want        string
+	}{
+		{"2026-10-18", time.January, "quarter", "2026-10-01"},
+		{"2026-10-18", time.January, "year", "2026-01-01"},
+		{"2026-10-18", time.July, "quarter", "2026-10-01"},
+		{"2026-10-18", time.July, "year", "2026-07-01"},
+		{"2026-06-30", time.July, "year", "2025-07-01"},
+		{"2026-03-31", time.April, "quarter", "2026-01-01"},
+		{"2026-04-01", time.April, "quarter", "2026-04-01"},
+		{"2026-02-14", time.November, "quarter", "2026-02-01"},
+		{"2026-01-31", time.November, "quarter", "2025-11-01"},
+		{"2026-01-31", time.November, "year", "2025-11-01"},
+	}
+
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		if got := bucketStart(d, tt.interval, tt.fiscalStart).Format("2006-01-02"); got != tt.want {
+			t.Errorf("%s %s from %s: got %s, want %s", tt.date, tt.interval, tt.fiscalStart, got, tt.want)
+		}
+	}
+}
@@ -1,17 +1,32 @@
 package services
 
 import (
+	"errors"
 	"fmt"
-	"time"
 
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
 	"github.com/agency-finance-reality/server/internal/models"
 	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
 )
 
+var ErrRetainerNotFound = errors.New("retainer not found")
+
+// RetainerUpdate changes the fields that are non-nil.
+type RetainerUpdate struct {
+	// ChurnProbability is the estimated chance the retainer is lost in any
+	// given month, used by the runway simulation
+	ChurnProbability *float64
+	BillingDay       *int
+}
+
 type ClientService interface {
 	CreateClient(agencyID string, name string) (*models.ClientView, error)
 	GetClients(agencyID string) ([]models.ClientView, error)
-	CreateRetainer(agencyID string, clientID string, amount float64) error
+	CreateRetainer(agencyID string, clientID string, amount float64, churnProbability float64, billingDay int) error
+	ListRetainers(agencyID string) ([]models.RetainerView, error)
+	UpdateRetainer(agencyID string, id string, update RetainerUpdate) error
 	GetRetainerSummary(agencyID string) (*models.RetainerSummaryView, error)
 }
 
@@ -19,17 +34,29 @@ type clientService struct {
 	clientRepo   repository.ClientRepository
 	retainerRepo repository.RetainerRepository
 	financeRepo  repository.FinanceRepository
+	settingsRepo repository.SettingsRepository
+	burn         burnCalculator
+	events       publisher
+	clock        clock.Clock
 }
 
 func NewClientService(
 	clientRepo repository.ClientRepository,
 	retainerRepo repository.RetainerRepository,
 	financeRepo repository.FinanceRepository,
+	recurringRepo repository.RecurringCostRepository,
+	settingsRepo repository.SettingsRepository,
+	bus *events.Bus,
+	clk clock.Clock,
 ) ClientService {
 	return &clientService{
 		clientRepo:   clientRepo,
 		retainerRepo: retainerRepo,
 		financeRepo:  financeRepo,
+		settingsRepo: settingsRepo,
+		burn:         burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		events:       publisher{bus: bus, clock: clk},
+		clock:        clk,
 	}
 }
 
@@ -38,11 +65,13 @@ func (s *clientService) CreateClient(agencyID string, name string) (*models.Clie
 	if err != nil {
 		return nil, err
 	}
-	return &models.ClientView{
+	view := &models.ClientView{
 		ID:     client.ID,
 		Name:   client.Name,
 		Status: client.Status,
-	}, nil
+	}
+	s.events.publish(events.ClientCreated, agencyID, view)
+	return view, nil
 }
 
 func (s *clientService) GetClients(agencyID string) ([]models.ClientView, error) {
@@ -61,7 +90,8 @@ func (s *clientService) GetClients(agencyID string) ([]models.ClientView, error)
 	return views, nil
 }
 
-func (s *clientService) CreateRetainer(agencyID string, clientID string, amount float64) error {
+// CreateRetainer defaults billingDay to the 1st when zero.
+func (s *clientService) CreateRetainer(agencyID string, clientID string, amount float64, churnProbability float64, billingDay int) error {
 	exists, err := s.retainerRepo.HasActiveRetainer(clientID)
 	if err != nil {
 		return err
@@ -69,7 +99,52 @@ func (s *clientService) CreateRetainer(agencyID string, clientID string, amount
 	if exists {
 		return fmt.Errorf("client already has active retainer")
 	}
-	return s.retainerRepo.Create(agencyID, clientID, amount)
+	if billingDay == 0 {
+		billingDay = 1
+	}
+	retainer, err := s.retainerRepo.Create(agencyID, clientID, amount, churnProbability, billingDay)
+	if err != nil {
+		return err
+	}
+	s.events.publish(events.RetainerCreated, agencyID, toRetainerView(*retainer))
+	return nil
+}
+
+func (s *clientService) ListRetainers(agencyID string) ([]models.RetainerView, error) {
+	retainers, err := s.retainerRepo.ListActive(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.RetainerView, len(retainers))
+	for i, r := range retainers {
+		views[i] = toRetainerView(r)
+	}
+	return views, nil
+}
+
+func toRetainerView(r repository.RetainerEntity) models.RetainerView {
+	return models.RetainerView{
+		ID:               r.ID,
+		ClientID:         r.ClientID,
+		MonthlyAmount:    r.MonthlyAmount,
+		ChurnProbability: r.ChurnProbability,
+		BillingDay:       r.BillingDay,
+	}
+}
+
+func (s *clientService) UpdateRetainer(agencyID string, id string, update RetainerUpdate) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrRetainerNotFound
+	}
+	updated, err := s.retainerRepo.Update(agencyID, id, update.ChurnProbability, update.BillingDay)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrRetainerNotFound
+	}
+	s.events.publish(events.RetainerUpdated, agencyID, nil)
+	return nil
 }
 
 func (s *clientService) GetRetainerSummary(agencyID string) (*models.RetainerSummaryView, error) {
@@ -78,11 +153,16 @@ func (s *clientService) GetRetainerSummary(agencyID string) (*models.RetainerSum
 		return nil, err
 	}
 
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	fixed, err := s.financeRepo.SumFixedCostsInRange(agencyID, thirtyDaysAgo)
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	figures, err := s.burn.compute(ac, agencyID)
 	if err != nil {
 		return nil, err
 	}
+	fixed := figures.monthly()
 
 	view := &models.RetainerSummaryView{
 		TotalRetainerRevenue: total,
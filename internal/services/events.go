@@ -0,0 +1,21 @@
+package services
+
+import (
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
+)
+
+// publisher raises events for a service's writes once they've succeeded.
+type publisher struct {
+	bus   *events.Bus
+	clock clock.Clock
+}
+
+func (p publisher) publish(eventType string, agencyID string, data interface{}) {
+	p.bus.Publish(events.Event{
+		Type:       eventType,
+		AgencyID:   agencyID,
+		OccurredAt: p.clock.Now(),
+		Data:       data,
+	})
+}
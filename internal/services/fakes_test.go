@@ -0,0 +1,256 @@
+package services
+
+import (
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/notify"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+// The fakes below embed the repository interface they stand in for, so a
+// test only implements the methods its service calls; anything else panics.
+
+type fakeSettingsRepo struct {
+	repository.SettingsRepository
+	settings repository.AgencySettingsEntity
+}
+
+func (r *fakeSettingsRepo) Get(agencyID string) (*repository.AgencySettingsEntity, error) {
+	s := r.settings
+	s.AgencyID = agencyID
+	return &s, nil
+}
+
+type fakeAgencyRepo struct {
+	repository.AgencyRepository
+	createdAt time.Time
+}
+
+func (r *fakeAgencyRepo) GetByID(id string) (*repository.AgencyEntity, error) {
+	return &repository.AgencyEntity{ID: id, CreatedAt: r.createdAt}, nil
+}
+
+// fakeFinanceRepo records the dates ledger writes and daily sums are made for.
+type fakeFinanceRepo struct {
+	repository.FinanceRepository
+	revenues []repository.RevenueEntity
+	costs    []repository.CostEntity
+	sumDates []string
+	// monthlyTotals is returned as is by MonthlyTotals
+	monthlyTotals []repository.MonthlyTotalEntity
+	// oneOffFixed and oneOffVariable are returned by the trailing burn sums
+	oneOffFixed    float64
+	oneOffVariable float64
+}
+
+func (r *fakeFinanceRepo) AddRevenue(agencyID string, date string, amount float64, source string) (string, error) {
+	id := "revenue-" + date
+	r.revenues = append(r.revenues, repository.RevenueEntity{ID: id, Date: date, Amount: amount, Source: source})
+	return id, nil
+}
+
+func (r *fakeFinanceRepo) GetRevenue(agencyID string, id string) (*repository.RevenueEntity, error) {
+	for i := range r.revenues {
+		if r.revenues[i].ID == id {
+			return &r.revenues[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFinanceRepo) AddCost(agencyID string, date string, amount float64, costType string, label string, category string) (string, error) {
+	id := "cost-" + date
+	r.costs = append(r.costs, repository.CostEntity{ID: id, Date: date, Amount: amount, Type: costType, Label: label, Category: category})
+	return id, nil
+}
+
+func (r *fakeFinanceRepo) GetCost(agencyID string, id string) (*repository.CostEntity, error) {
+	for i := range r.costs {
+		if r.costs[i].ID == id {
+			return &r.costs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeFinanceRepo) SumRevenues(agencyID string, date string) (float64, error) {
+	r.sumDates = append(r.sumDates, date)
+	return 0, nil
+}
+
+func (r *fakeFinanceRepo) SumCosts(agencyID string, date string) (float64, error) {
+	r.sumDates = append(r.sumDates, date)
+	return 0, nil
+}
+
+func (r *fakeFinanceRepo) MonthlyTotals(agencyID string, from string, to string) ([]repository.MonthlyTotalEntity, error) {
+	return r.monthlyTotals, nil
+}
+
+func (r *fakeFinanceRepo) SumOneOffFixedCostsBetween(agencyID string, from string, to string) (float64, error) {
+	return r.oneOffFixed, nil
+}
+
+func (r *fakeFinanceRepo) SumOneOffVariableCostsBetween(agencyID string, from string, to string) (float64, error) {
+	return r.oneOffVariable, nil
+}
+
+func (r *fakeFinanceRepo) SumFixedCostsInRange(agencyID string, startDate string) (float64, error) {
+	return 0, nil
+}
+
+func (r *fakeFinanceRepo) SumAllRevenuesInRange(agencyID string, startDate string) (float64, error) {
+	return 0, nil
+}
+
+func (r *fakeFinanceRepo) SumAllCostsInRange(agencyID string, startDate string) (float64, error) {
+	return 0, nil
+}
+
+type fakeTimeRepo struct {
+	repository.TimeEntryRepository
+	dates       []string
+	windowStart string
+	// hours is returned by SumHoursInRange
+	hours float64
+}
+
+func (r *fakeTimeRepo) Add(agencyID string, clientID *string, date string, hours float64) error {
+	r.dates = append(r.dates, date)
+	return nil
+}
+
+func (r *fakeTimeRepo) SumHoursInRange(agencyID string, startDate string) (float64, error) {
+	r.windowStart = startDate
+	return r.hours, nil
+}
+
+type fakeBankRepo struct {
+	repository.BankAccountRepository
+	balances []repository.AccountBalanceEntity
+}
+
+func (r *fakeBankRepo) LatestBalances(agencyID string, onOrBefore string) ([]repository.AccountBalanceEntity, error) {
+	return r.balances, nil
+}
+
+type fakeRetainerRepo struct {
+	repository.RetainerRepository
+	retainers []repository.RetainerEntity
+}
+
+func (r *fakeRetainerRepo) ListActive(agencyID string) ([]repository.RetainerEntity, error) {
+	return r.retainers, nil
+}
+
+type fakeRecurringCostRepo struct {
+	repository.RecurringCostRepository
+	costs []repository.RecurringCostEntity
+}
+
+func (r *fakeRecurringCostRepo) List(agencyID string) ([]repository.RecurringCostEntity, error) {
+	return r.costs, nil
+}
+
+type fakePlannedItemRepo struct {
+	repository.PlannedItemRepository
+}
+
+func (r *fakePlannedItemRepo) ListBetween(agencyID string, from string, to string) ([]repository.PlannedItemEntity, error) {
+	return nil, nil
+}
+
+type fakeInvoiceRepo struct {
+	repository.InvoiceRepository
+}
+
+func (r *fakeInvoiceRepo) List(agencyID string, outstandingOnly bool) ([]repository.InvoiceEntity, error) {
+	return nil, nil
+}
+
+// fakeAlertRuleRepo holds a single rule and claims it the way the SQL does.
+type fakeAlertRuleRepo struct {
+	repository.AlertRuleRepository
+	rule repository.AlertRuleEntity
+}
+
+func (r *fakeAlertRuleRepo) List(agencyID string, enabledOnly bool) ([]repository.AlertRuleEntity, error) {
+	return []repository.AlertRuleEntity{r.rule}, nil
+}
+
+func (r *fakeAlertRuleRepo) ClaimFiring(id string, now time.Time) (bool, error) {
+	cooldown := time.Duration(r.rule.CooldownMinutes) * time.Minute
+	if r.rule.BreachedSince != nil || (r.rule.LastFiredAt != nil && r.rule.LastFiredAt.After(now.Add(-cooldown))) {
+		return false, nil
+	}
+	r.rule.BreachedSince, r.rule.LastFiredAt = &now, &now
+	return true, nil
+}
+
+func (r *fakeAlertRuleRepo) ClearBreach(id string) error {
+	r.rule.BreachedSince = nil
+	return nil
+}
+
+type fakeAlertRepo struct {
+	repository.AlertRepository
+	alerts      []repository.AlertEntity
+	nextAttempt map[string]time.Time
+}
+
+func (r *fakeAlertRepo) Create(a repository.AlertEntity, retryAt time.Time) (*repository.AlertEntity, error) {
+	a.ID = "alert-" + a.FiredAt.Format(time.RFC3339)
+	r.alerts = append(r.alerts, a)
+	if r.nextAttempt == nil {
+		r.nextAttempt = make(map[string]time.Time)
+	}
+	r.nextAttempt[a.ID] = retryAt
+	return &a, nil
+}
+
+func (r *fakeAlertRepo) LatestUndelivered(ruleID string, since time.Time) (*repository.AlertEntity, error) {
+	for i := len(r.alerts) - 1; i >= 0; i-- {
+		a := r.alerts[i]
+		if *a.RuleID != ruleID || a.FiredAt.Before(since) {
+			continue
+		}
+		if a.DeliveredAt != nil {
+			return nil, nil
+		}
+		return &a, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeAlertRepo) ClaimRetry(id string, now time.Time, retryAt time.Time) (bool, error) {
+	if r.nextAttempt[id].After(now) {
+		return false, nil
+	}
+	r.nextAttempt[id] = retryAt
+	return true, nil
+}
+
+func (r *fakeAlertRepo) RecordDelivery(id string, deliveredAt time.Time, deliveryErr *string) error {
+	for i := range r.alerts {
+		if r.alerts[i].ID == id {
+			r.alerts[i].DeliveryError = deliveryErr
+			if deliveryErr == nil {
+				r.alerts[i].DeliveredAt = &deliveredAt
+			}
+		}
+	}
+	return nil
+}
+
+// fakeChannel counts sends and fails them with err.
+type fakeChannel struct {
+	err   error
+	sends int
+}
+
+func (c *fakeChannel) Validate(target string) error { return nil }
+
+func (c *fakeChannel) Send(target string, msg notify.Message) error {
+	c.sends++
+	return c.err
+}
@@ -1,50 +1,143 @@
 package services
 
 import (
-	"time"
+	"errors"
+	"fmt"
 
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
 	"github.com/agency-finance-reality/server/internal/models"
 	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
 )
 
+var ErrEntryNotFound = errors.New("entry not found")
+
+// RevenueUpdate and CostUpdate are partial updates; nil fields are unchanged.
+type RevenueUpdate struct {
+	Date   *string
+	Amount *float64
+	Source *string
+}
+
+type CostUpdate struct {
+	Date     *string
+	Amount   *float64
+	Type     *string
+	Label    *string
+	Category *string
+}
+
 type FinanceService interface {
-	RecordCashSnapshot(agencyID string, cashBalance float64) error
-	GetDailySnapshot(agencyID string) (*models.DailySnapshotView, error)
-	AddRevenue(agencyID string, amount float64, source string) error
-	AddCost(agencyID string, amount float64, costType string, label string, category string) error
+	RecordCashSnapshot(agencyID string, userID string, date string, cashBalance float64) (*models.DailySnapshotView, error)
+	GetDailySnapshot(agencyID string, date string) (*models.DailySnapshotView, error)
+	GetCashHistory(agencyID string, from string, to string, interval string) (*models.CashHistoryView, error)
+	AddRevenue(agencyID string, date string, amount float64, source string) (string, error)
+	AddCost(agencyID string, date string, amount float64, costType string, label string, category string) (string, error)
+	ListRevenues(agencyID string, from string, to string) ([]models.RevenueView, error)
+	ListCosts(agencyID string, from string, to string) ([]models.CostView, error)
+	UpdateRevenue(agencyID string, id string, update RevenueUpdate) (*models.RevenueView, error)
+	UpdateCost(agencyID string, id string, update CostUpdate) (*models.CostView, error)
+	DeleteRevenue(agencyID string, id string) error
+	DeleteCost(agencyID string, id string) error
 	GetDailySummary(agencyID string) (*models.DailySummaryView, error)
-	GetSurvivalMetrics(agencyID string) (*models.SurvivalMetricsView, error)
+	GetSurvivalMetrics(agencyID string, excludeRestricted bool) (*models.SurvivalMetricsView, error)
 	GetRealityScore(agencyID string) (*models.RealityScoreView, error)
+	RefreshScoreStatus(agencyID string) error
+	RefreshScoreStatuses() error
 	GetCostBreakdown(agencyID string) (*models.CostBreakdownView, error)
 }
 
 type financeService struct {
+	agencyRepo   repository.AgencyRepository
 	cashRepo     repository.CashSnapshotRepository
+	bankRepo     repository.BankAccountRepository
 	financeRepo  repository.FinanceRepository
 	retainerRepo repository.RetainerRepository
 	timeRepo     repository.TimeEntryRepository
+	settingsRepo repository.SettingsRepository
+	scoringRepo  repository.ScoringProfileRepository
+	statusRepo   repository.ScoreStatusRepository
+	metrics      metricsLoader
+	events       publisher
+	clock        clock.Clock
 }
 
 func NewFinanceService(
+	agencyRepo repository.AgencyRepository,
 	cashRepo repository.CashSnapshotRepository,
+	bankRepo repository.BankAccountRepository,
 	financeRepo repository.FinanceRepository,
+	recurringRepo repository.RecurringCostRepository,
 	retainerRepo repository.RetainerRepository,
 	timeRepo repository.TimeEntryRepository,
+	settingsRepo repository.SettingsRepository,
+	scoringRepo repository.ScoringProfileRepository,
+	statusRepo repository.ScoreStatusRepository,
+	bus *events.Bus,
+	clk clock.Clock,
 ) FinanceService {
 	return &financeService{
+		agencyRepo:   agencyRepo,
 		cashRepo:     cashRepo,
+		bankRepo:     bankRepo,
 		financeRepo:  financeRepo,
 		retainerRepo: retainerRepo,
 		timeRepo:     timeRepo,
+		settingsRepo: settingsRepo,
+		scoringRepo:  scoringRepo,
+		statusRepo:   statusRepo,
+		metrics: metricsLoader{
+			bankRepo:     bankRepo,
+			cashRepo:     cashRepo,
+			financeRepo:  financeRepo,
+			retainerRepo: retainerRepo,
+			timeRepo:     timeRepo,
+			burn:         burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		events: publisher{bus: bus, clock: clk},
+		clock:  clk,
 	}
 }
 
-func (s *financeService) RecordCashSnapshot(agencyID string, cashBalance float64) error {
-	return s.cashRepo.CreateDaily(agencyID, cashBalance)
+// RecordCashSnapshot sets the balance for date (today when empty), replacing
+// any earlier value for that day. Agencies with bank accounts record balances
+// per account instead.
+func (s *financeService) RecordCashSnapshot(agencyID string, userID string, date string, cashBalance float64) (*models.DailySnapshotView, error) {
+	entryDate, err := s.resolveEntryDate(agencyID, date)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.bankRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) > 0 {
+		return nil, ErrCashTrackedByAccounts
+	}
+	if err := s.cashRepo.Upsert(agencyID, entryDate, cashBalance, userID); err != nil {
+		return nil, err
+	}
+	view, err := s.GetDailySnapshot(agencyID, entryDate)
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(events.CashSnapshotRecorded, agencyID, view)
+	return view, nil
 }
 
-func (s *financeService) GetDailySnapshot(agencyID string) (*models.DailySnapshotView, error) {
-	snap, err := s.cashRepo.GetToday(agencyID)
+// GetDailySnapshot returns the snapshot for date (today when empty), or nil
+// if none was recorded.
+func (s *financeService) GetDailySnapshot(agencyID string, date string) (*models.DailySnapshotView, error) {
+	if date == "" {
+		ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+		if err != nil {
+			return nil, err
+		}
+		date = ac.today()
+	}
+
+	snap, err := s.cashRepo.GetByDate(agencyID, date)
 	if err != nil || snap == nil {
 		return nil, err
 	}
@@ -61,197 +154,326 @@ func (s *financeService) GetDailySnapshot(agencyID string) (*models.DailySnapsho
 		view.Delta = &d
 	}
 
+	revisions, err := s.cashRepo.ListRevisions(agencyID, snap.Date)
+	if err != nil {
+		return nil, err
+	}
+	view.Revisions = make([]models.CashSnapshotRevisionView, len(revisions))
+	for i, r := range revisions {
+		view.Revisions[i] = models.CashSnapshotRevisionView{
+			PreviousBalance: r.PreviousBalance,
+			NewBalance:      r.NewBalance,
+			ChangedBy:       r.ChangedBy,
+			ChangedAt:       r.ChangedAt,
+		}
+	}
+
 	return view, nil
 }
 
-func (s *financeService) AddRevenue(agencyID string, amount float64, source string) error {
-	return s.financeRepo.AddRevenue(agencyID, amount, source)
+func (s *financeService) AddRevenue(agencyID string, date string, amount float64, source string) (string, error) {
+	entryDate, err := s.resolveEntryDate(agencyID, date)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.financeRepo.AddRevenue(agencyID, entryDate, amount, source)
+	if err != nil {
+		return "", err
+	}
+	rev, err := s.financeRepo.GetRevenue(agencyID, id)
+	if err != nil {
+		return "", err
+	}
+	if rev != nil {
+		s.events.publish(events.RevenueCreated, agencyID, toRevenueView(*rev))
+	}
+	return id, nil
 }
 
-func (s *financeService) AddCost(agencyID string, amount float64, costType string, label string, category string) error {
-	return s.financeRepo.AddCost(agencyID, amount, costType, label, category)
+func (s *financeService) AddCost(agencyID string, date string, amount float64, costType string, label string, category string) (string, error) {
+	entryDate, err := s.resolveEntryDate(agencyID, date)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.financeRepo.AddCost(agencyID, entryDate, amount, costType, label, category)
+	if err != nil {
+		return "", err
+	}
+	if err := publishCostCreated(s.financeRepo, s.events, agencyID, id); err != nil {
+		return "", err
+	}
+	return id, nil
 }
 
-func (s *financeService) GetDailySummary(agencyID string) (*models.DailySummaryView, error) {
-	today := time.Now().Format("2006-01-02")
-	rev, err := s.financeRepo.SumRevenues(agencyID, today)
+// publishCostCreated announces a newly written cost, whether added through
+// the ledger or posted from a recurring template.
+func publishCostCreated(financeRepo repository.FinanceRepository, p publisher, agencyID string, id string) error {
+	cost, err := financeRepo.GetCost(agencyID, id)
+	if err != nil || cost == nil {
+		return err
+	}
+	p.publish(events.CostCreated, agencyID, toCostView(*cost))
+	return nil
+}
+
+func (s *financeService) ListRevenues(agencyID string, from string, to string) ([]models.RevenueView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
 	if err != nil {
 		return nil, err
 	}
-	cost, err := s.financeRepo.SumCosts(agencyID, today)
+	from, to, err = ac.dateRange(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.DailySummaryView{
-		Date:    today,
-		Revenue: rev,
-		Costs:   cost,
-		Net:     rev - cost,
-	}, nil
+	entities, err := s.financeRepo.ListRevenues(agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.RevenueView, len(entities))
+	for i, e := range entities {
+		views[i] = *toRevenueView(e)
+	}
+	return views, nil
 }
 
-func (s *financeService) GetSurvivalMetrics(agencyID string) (*models.SurvivalMetricsView, error) {
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-
-	cash, err := s.cashRepo.GetLatest(agencyID)
-	if err != nil || cash == nil {
+func (s *financeService) ListCosts(agencyID string, from string, to string) ([]models.CostView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
 		return nil, err
 	}
-
-	burn, err := s.financeRepo.SumFixedCostsInRange(agencyID, thirtyDaysAgo)
+	from, to, err = ac.dateRange(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	retainers, err := s.retainerRepo.SumActiveRetainers(agencyID)
+	entities, err := s.financeRepo.ListCosts(agencyID, from, to)
 	if err != nil {
 		return nil, err
 	}
+	views := make([]models.CostView, len(entities))
+	for i, e := range entities {
+		views[i] = *toCostView(e)
+	}
+	return views, nil
+}
 
-	view := &models.SurvivalMetricsView{
-		CashBalance:     *cash,
-		MonthlyBurn:     burn,
-		TotalRetainers:  retainers,
-		OperatingMargin: retainers - burn,
+func (s *financeService) UpdateRevenue(agencyID string, id string, update RevenueUpdate) (*models.RevenueView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrEntryNotFound
+	}
+	rev, err := s.financeRepo.GetRevenue(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, ErrEntryNotFound
 	}
 
-	if burn > 0 {
-		runway := *cash / burn
-		runway = float64(int(runway*10)) / 10
-		view.RunwayMonths = &runway
+	if update.Date != nil && *update.Date != rev.Date {
+		rev.Date, err = s.resolveEntryDate(agencyID, *update.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if update.Amount != nil {
+		rev.Amount = *update.Amount
+	}
+	if update.Source != nil {
+		rev.Source = *update.Source
 	}
 
+	if err := s.financeRepo.UpdateRevenue(*rev); err != nil {
+		return nil, err
+	}
+	view := toRevenueView(*rev)
+	s.events.publish(events.RevenueUpdated, agencyID, view)
 	return view, nil
 }
 
-func (s *financeService) GetRealityScore(agencyID string) (*models.RealityScoreView, error) {
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	var result models.RealityScoreView
-
-	// A. Retainer Safety (25 pts)
-	var totalRetainer, fixedCosts float64
-	totalRetainer, _ = s.retainerRepo.SumActiveRetainers(agencyID)
-	fixedCosts, _ = s.financeRepo.SumFixedCostsInRange(agencyID, thirtyDaysAgo)
-	if fixedCosts > 0 {
-		coverage := totalRetainer / fixedCosts
-		if coverage >= 1.5 {
-			result.Breakdown.RetainerSafety = 25
-		} else if coverage >= 1.2 {
-			result.Breakdown.RetainerSafety = 20
-		} else if coverage >= 1.0 {
-			result.Breakdown.RetainerSafety = 15
-		} else if coverage >= 0.8 {
-			result.Breakdown.RetainerSafety = 10
-		}
+func (s *financeService) UpdateCost(agencyID string, id string, update CostUpdate) (*models.CostView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrEntryNotFound
+	}
+	cost, err := s.financeRepo.GetCost(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cost == nil {
+		return nil, ErrEntryNotFound
 	}
 
-	// B. Runway Health (20 pts)
-	cash, _ := s.cashRepo.GetLatest(agencyID)
-	if cash != nil && fixedCosts > 0 {
-		runway := *cash / fixedCosts
-		if runway >= 6 {
-			result.Breakdown.Runway = 20
-		} else if runway >= 4 {
-			result.Breakdown.Runway = 15
-		} else if runway >= 2 {
-			result.Breakdown.Runway = 8
-		} else if runway >= 1 {
-			result.Breakdown.Runway = 4
+	if update.Date != nil && *update.Date != cost.Date {
+		cost.Date, err = s.resolveEntryDate(agencyID, *update.Date)
+		if err != nil {
+			return nil, err
 		}
 	}
+	if update.Amount != nil {
+		cost.Amount = *update.Amount
+	}
+	if update.Type != nil {
+		cost.Type = *update.Type
+	}
+	if update.Label != nil {
+		cost.Label = *update.Label
+	}
+	if update.Category != nil {
+		cost.Category = *update.Category
+	}
 
-	// C. Client Concentration (20 pts)
-	if totalRetainer > 0 {
-		maxRetainer, _ := s.retainerRepo.GetMaxRetainer(agencyID)
-		topPct := (maxRetainer / totalRetainer) * 100
-		if topPct < 30 {
-			result.Breakdown.ClientConcentration = 20
-		} else if topPct < 40 {
-			result.Breakdown.ClientConcentration = 15
-		} else if topPct < 50 {
-			result.Breakdown.ClientConcentration = 8
-		} else if topPct < 60 {
-			result.Breakdown.ClientConcentration = 4
-		}
+	if err := s.financeRepo.UpdateCost(*cost); err != nil {
+		return nil, err
 	}
+	view := toCostView(*cost)
+	s.events.publish(events.CostUpdated, agencyID, view)
+	return view, nil
+}
 
-	// D. Profitability (20 pts) - 30-day rolling window (Ticket 09 fix)
-	rev, _ := s.financeRepo.SumAllRevenuesInRange(agencyID, thirtyDaysAgo)
-	costs, _ := s.financeRepo.SumAllCostsInRange(agencyID, thirtyDaysAgo)
-	if rev > 0 {
-		margin := ((rev - costs) / rev) * 100
-		if margin >= 20 {
-			result.Breakdown.Profitability = 20
-		} else if margin >= 10 {
-			result.Breakdown.Profitability = 15
-		} else if margin >= 0 {
-			result.Breakdown.Profitability = 8
-		} else if margin >= -10 {
-			result.Breakdown.Profitability = 4
-		}
+func (s *financeService) DeleteRevenue(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrEntryNotFound
+	}
+	deleted, err := s.financeRepo.DeleteRevenue(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEntryNotFound
 	}
+	s.events.publish(events.RevenueDeleted, agencyID, models.DeletedView{ID: id})
+	return nil
+}
 
-	// E. Capacity Pressure (15 pts)
-	usedHours, _ := s.timeRepo.SumHoursInRange(agencyID, thirtyDaysAgo)
-	utilization := (usedHours / 160.0) * 100 // Hardcoded capacity 160
-	if utilization >= 60 && utilization <= 85 {
-		result.Breakdown.CapacityPressure = 15
-	} else if utilization >= 50 && utilization < 60 {
-		result.Breakdown.CapacityPressure = 10
-	} else if utilization >= 40 && utilization < 50 {
-		result.Breakdown.CapacityPressure = 6
-	} else if utilization > 85 && utilization <= 100 {
-		result.Breakdown.CapacityPressure = 6
-	}
-
-	result.Score = result.Breakdown.RetainerSafety + result.Breakdown.Runway + result.Breakdown.ClientConcentration + result.Breakdown.Profitability + result.Breakdown.CapacityPressure
-	if result.Score >= 80 {
-		result.Status = "Healthy"
-	} else if result.Score >= 60 {
-		result.Status = "Watch"
-	} else if result.Score >= 40 {
-		result.Status = "At Risk"
-	} else {
-		result.Status = "Danger"
+func (s *financeService) DeleteCost(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrEntryNotFound
+	}
+	deleted, err := s.financeRepo.DeleteCost(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEntryNotFound
 	}
+	s.events.publish(events.CostDeleted, agencyID, models.DeletedView{ID: id})
+	return nil
+}
 
-	if cash != nil {
-		result.CashOnHand = *cash
+func (s *financeService) resolveEntryDate(agencyID string, date string) (string, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return "", err
 	}
-	result.CommittedRetainers = totalRetainer
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return "", err
+	}
+	return ac.entryDate(date, agency.CreatedAt)
+}
 
-	// Primary Risk Attribution (Ticket 11)
-	if result.Score >= 80 {
-		result.PrimaryRisk = "Healthy"
-	} else {
-		// Priority order
-		if fixedCosts > totalRetainer && fixedCosts > 0 {
-			result.PrimaryRisk = "High Fixed Costs"
-		} else if totalRetainer/fixedCosts < 1.0 {
-			result.PrimaryRisk = "Low Retainer Base"
-		} else {
-			maxRetainer, _ := s.retainerRepo.GetMaxRetainer(agencyID)
-			topPct := 0.0
-			if totalRetainer > 0 {
-				topPct = (maxRetainer / totalRetainer) * 100
-			}
-			if topPct > 60 {
-				result.PrimaryRisk = "Client Concentration"
-			} else if cash != nil && fixedCosts > 0 && (*cash/fixedCosts) < 2 {
-				result.PrimaryRisk = "Low Runway"
-			} else {
-				result.PrimaryRisk = "Healthy"
-			}
-		}
+func (s *financeService) GetDailySummary(agencyID string) (*models.DailySummaryView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := ac.today()
+	rev, err := s.financeRepo.SumRevenues(agencyID, today)
+	if err != nil {
+		return nil, err
+	}
+	cost, err := s.financeRepo.SumCosts(agencyID, today)
+	if err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return &models.DailySummaryView{
+		Date:    today,
+		Revenue: rev,
+		Costs:   cost,
+		Net:     rev - cost,
+	}, nil
+}
+
+// GetSurvivalMetrics reports the consolidated cash position and runway against
+// normalized monthly burn. With excludeRestricted, runway is computed from
+// unrestricted accounts only.
+func (s *financeService) GetSurvivalMetrics(agencyID string, excludeRestricted bool) (*models.SurvivalMetricsView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	in, err := s.metrics.load(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	return computeSurvival(in, excludeRestricted), nil
+}
+
+func (s *financeService) GetRealityScore(agencyID string) (*models.RealityScoreView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	in, err := s.metrics.load(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := loadScoring(s.scoringRepo, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	return computeRealityScore(in, sc), nil
+}
+
+// RefreshScoreStatus recomputes the reality score and announces
+// score.status_changed if its status differs from the last one seen. The
+// first status seen for an agency isn't announced.
+func (s *financeService) RefreshScoreStatus(agencyID string) error {
+	score, err := s.GetRealityScore(agencyID)
+	if err != nil {
+		return err
+	}
+	previous, changed, err := s.statusRepo.Swap(agencyID, score.Status, s.clock.Now())
+	if err != nil {
+		return err
+	}
+	if changed && previous != nil {
+		s.events.publish(events.ScoreStatusChanged, agencyID, models.ScoreStatusChangeView{
+			From:        *previous,
+			To:          score.Status,
+			Score:       score.Score,
+			PrimaryRisk: score.PrimaryRisk,
+		})
+	}
+	return nil
+}
+
+// RefreshScoreStatuses refreshes every agency's status. It runs from the
+// scheduler to catch changes no write causes, such as old entries leaving
+// the lookback window.
+func (s *financeService) RefreshScoreStatuses() error {
+	ids, err := s.agencyRepo.ListIDs()
+	if err != nil {
+		return err
+	}
+	var failed []error
+	for _, id := range ids {
+		if err := s.RefreshScoreStatus(id); err != nil {
+			failed = append(failed, fmt.Errorf("agency %s: %w", id, err))
+		}
+	}
+	return errors.Join(failed...)
 }
 
 func (s *financeService) GetCostBreakdown(agencyID string) (*models.CostBreakdownView, error) {
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	breakdown, err := s.financeRepo.GetGroupedFixedCosts(agencyID, thirtyDaysAgo)
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown, err := s.financeRepo.GetGroupedFixedCosts(agencyID, ac.windowStart())
 	if err != nil {
 		return nil, err
 	}
@@ -286,3 +508,26 @@ func (s *financeService) GetCostBreakdown(agencyID string) (*models.CostBreakdow
 
 	return view, nil
 }
+
+func toRevenueView(e repository.RevenueEntity) *models.RevenueView {
+	return &models.RevenueView{
+		ID:        e.ID,
+		Date:      e.Date,
+		Amount:    e.Amount,
+		Source:    e.Source,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func toCostView(e repository.CostEntity) *models.CostView {
+	return &models.CostView{
+		ID:              e.ID,
+		Date:            e.Date,
+		Amount:          e.Amount,
+		Type:            e.Type,
+		Label:           e.Label,
+		Category:        e.Category,
+		RecurringCostID: e.RecurringCostID,
+		CreatedAt:       e.CreatedAt,
+	}
+}
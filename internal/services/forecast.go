@@ -0,0 +1,387 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoCashSnapshot      = errors.New("no cash snapshot recorded")
+	ErrPlannedItemNotFound = errors.New("planned item not found")
+)
+
+// maxForecastMonths bounds how far ahead a forecast can project.
+const maxForecastMonths = 36
+
+type ForecastService interface {
+	GetCashForecast(agencyID string, months int, excludeRestricted bool) (*models.CashForecastView, error)
+	ListPlannedItems(agencyID string) ([]models.PlannedItemView, error)
+	CreatePlannedItem(agencyID string, date string, amount float64, direction string, label string) (*models.PlannedItemView, error)
+	DeletePlannedItem(agencyID string, id string) error
+	GetRunwayDistribution(agencyID string, params RunwaySimulationParams) (*models.RunwayDistributionView, error)
+}
+
+type forecastService struct {
+	agencyRepo   repository.AgencyRepository
+	bankRepo     repository.BankAccountRepository
+	cashRepo     repository.CashSnapshotRepository
+	financeRepo  repository.FinanceRepository
+	retainerRepo repository.RetainerRepository
+	plannedRepo  repository.PlannedItemRepository
+	settingsRepo repository.SettingsRepository
+	inputs       forecastLoader
+	clock        clock.Clock
+}
+
+func NewForecastService(
+	agencyRepo repository.AgencyRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	financeRepo repository.FinanceRepository,
+	retainerRepo repository.RetainerRepository,
+	recurringRepo repository.RecurringCostRepository,
+	plannedRepo repository.PlannedItemRepository,
+	invoiceRepo repository.InvoiceRepository,
+	settingsRepo repository.SettingsRepository,
+	clk clock.Clock,
+) ForecastService {
+	return &forecastService{
+		agencyRepo:   agencyRepo,
+		bankRepo:     bankRepo,
+		cashRepo:     cashRepo,
+		financeRepo:  financeRepo,
+		retainerRepo: retainerRepo,
+		plannedRepo:  plannedRepo,
+		settingsRepo: settingsRepo,
+		inputs: forecastLoader{
+			retainerRepo:  retainerRepo,
+			recurringRepo: recurringRepo,
+			plannedRepo:   plannedRepo,
+			invoiceRepo:   invoiceRepo,
+			burn:          burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		clock: clk,
+	}
+}
+
+// GetCashForecast projects the balance day by day from the current cash
+// position through the end of the given number of calendar months. Retainers
+// land on their billing day; recurring costs follow their cadence; planned
+// items and outstanding invoices land on their date, overdue invoices
+// tomorrow; costs entered by hand recur at their trailing average on the last
+// day of each month.
+func (s *forecastService) GetCashForecast(agencyID string, months int, excludeRestricted bool) (*models.CashForecastView, error) {
+	if months < 1 || months > maxForecastMonths {
+		return nil, fmt.Errorf("%w: months must be 1-%d", ErrInvalidDateRange, maxForecastMonths)
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := currentCashPosition(s.bankRepo, s.cashRepo, agencyID, ac.today())
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		return nil, ErrNoCashSnapshot
+	}
+	balance := position.total
+	if excludeRestricted {
+		balance = position.available()
+	}
+
+	today, end := forecastWindow(ac, months)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	in, err := s.inputs.load(ac, agencyID, tomorrow, end)
+	if err != nil {
+		return nil, err
+	}
+	flows := in.flows(tomorrow, end)
+
+	view := projectCash(balance, today, end, flows, ac.settings.CashSafetyFloor)
+	view.ExcludesRestricted = excludeRestricted
+	return view, nil
+}
+
+// forecastWindow returns today and the last projected date: the end of the
+// given number of calendar months, counting the month tomorrow falls in.
+func forecastWindow(ac *agencyContext, months int) (time.Time, time.Time) {
+	today, _ := time.Parse("2006-01-02", ac.today())
+	tomorrow := today.AddDate(0, 0, 1)
+	return today, time.Date(tomorrow.Year(), tomorrow.Month()+time.Month(months), 0, 0, 0, 0, 0, time.UTC)
+}
+
+// forecastInputs are the known future cash movements for an agency.
+type forecastInputs struct {
+	retainers []projectedRetainer
+	recurring []repository.RecurringCostEntity
+	planned   []repository.PlannedItemEntity
+	// invoices are outstanding only
+	invoices []repository.InvoiceEntity
+	// oneOffMonthly is the spend entered by hand rather than posted from
+	// templates, expected again each month
+	oneOffMonthly float64
+}
+
+// projectedRetainer is paid on billingDay of every month from from
+// (inclusive) until until (exclusive); empty bounds are open.
+type projectedRetainer struct {
+	clientID   string
+	amount     float64
+	billingDay int
+	from       string
+	until      string
+}
+
+func (r projectedRetainer) paidOn(date string) bool {
+	return (r.from == "" || date >= r.from) && (r.until == "" || date < r.until)
+}
+
+// Inflow sources in a cashMovement. Outflows use the cost category instead.
+const (
+	sourceRetainers = "retainers"
+	sourceInvoices  = "invoices"
+	sourcePlanned   = "planned"
+)
+
+// cashMovement is one projected movement. Amount is positive for inflows;
+// Kind is the inflow source or the outflow's cost category.
+type cashMovement struct {
+	date   string
+	amount float64
+	kind   string
+}
+
+type forecastLoader struct {
+	retainerRepo  repository.RetainerRepository
+	recurringRepo repository.RecurringCostRepository
+	plannedRepo   repository.PlannedItemRepository
+	invoiceRepo   repository.InvoiceRepository
+	burn          burnCalculator
+}
+
+func (l forecastLoader) load(ac *agencyContext, agencyID string, from time.Time, to time.Time) (*forecastInputs, error) {
+	var in forecastInputs
+	retainers, err := l.retainerRepo.ListActive(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range retainers {
+		in.retainers = append(in.retainers, projectedRetainer{clientID: r.ClientID, amount: r.MonthlyAmount, billingDay: r.BillingDay})
+	}
+	if in.recurring, err = l.recurringRepo.List(agencyID); err != nil {
+		return nil, err
+	}
+	if in.planned, err = l.plannedRepo.ListBetween(agencyID, from.Format("2006-01-02"), to.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	if in.invoices, err = l.invoiceRepo.List(agencyID, true); err != nil {
+		return nil, err
+	}
+	// The same trailing averages as burn, so runway and forecast agree
+	figures, err := l.burn.compute(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	in.oneOffMonthly = figures.oneOffAverage + figures.variableAverage
+	return &in, nil
+}
+
+// movements lists every projected movement between from and to. Overdue
+// invoices are expected on from.
+func (in *forecastInputs) movements(from time.Time, to time.Time) []cashMovement {
+	var movements []cashMovement
+	fromDate, toDate := from.Format("2006-01-02"), to.Format("2006-01-02")
+
+	for _, r := range in.retainers {
+		day := r.billingDay
+		if day == 0 {
+			day = 1
+		}
+		for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(to); m = m.AddDate(0, 1, 0) {
+			date := time.Date(m.Year(), m.Month(), day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+			if date >= fromDate && date <= toDate && r.paidOn(date) {
+				movements = append(movements, cashMovement{date: date, amount: r.amount, kind: sourceRetainers})
+			}
+		}
+	}
+
+	for _, inv := range in.invoices {
+		date := inv.DueDate
+		if date < fromDate {
+			date = fromDate
+		}
+		if date <= toDate {
+			movements = append(movements, cashMovement{date: date, amount: inv.Amount, kind: sourceInvoices})
+		}
+	}
+
+	for _, c := range in.recurring {
+		start := nextUnposted(c)
+		if start < fromDate {
+			start = fromDate
+		}
+		for _, date := range occurrences(c, start, toDate) {
+			movements = append(movements, cashMovement{date: date, amount: -c.Amount, kind: c.Category})
+		}
+	}
+
+	for _, item := range in.planned {
+		if item.Date < fromDate || item.Date > toDate {
+			continue
+		}
+		if item.Direction == repository.DirectionInflow {
+			movements = append(movements, cashMovement{date: item.Date, amount: item.Amount, kind: sourcePlanned})
+		} else {
+			movements = append(movements, cashMovement{date: item.Date, amount: -item.Amount, kind: "other"})
+		}
+	}
+
+	if in.oneOffMonthly > 0 {
+		for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(to); m = m.AddDate(0, 1, 0) {
+			date := m.AddDate(0, 1, -1).Format("2006-01-02")
+			if date >= fromDate && date <= toDate {
+				movements = append(movements, cashMovement{date: date, amount: -in.oneOffMonthly, kind: "other"})
+			}
+		}
+	}
+
+	return movements
+}
+
+// flows nets every projected movement between from and to by date.
+func (in *forecastInputs) flows(from time.Time, to time.Time) map[string]float64 {
+	flows := make(map[string]float64)
+	for _, m := range in.movements(from, to) {
+		flows[m.date] += m.amount
+	}
+	return flows
+}
+
+// projectCash walks the days after today, applying each day's net flow, and
+// summarizes the result by calendar month.
+func projectCash(balance float64, today time.Time, end time.Time, flows map[string]float64, floor float64) *models.CashForecastView {
+	view := &models.CashForecastView{
+		StartDate:       today.Format("2006-01-02"),
+		EndDate:         end.Format("2006-01-02"),
+		StartingBalance: roundCents(balance),
+		SafetyFloor:     floor,
+		Months:          []models.CashForecastMonth{},
+	}
+
+	var month *models.CashForecastMonth
+	for d := today.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01")
+		if month == nil || month.Month != key {
+			if month != nil {
+				view.Months = append(view.Months, roundMonth(*month))
+			}
+			month = &models.CashForecastMonth{Month: key, OpeningBalance: balance, LowestBalance: balance}
+		}
+
+		date := d.Format("2006-01-02")
+		flow := flows[date]
+		if flow > 0 {
+			month.Inflows += flow
+		} else {
+			month.Outflows -= flow
+		}
+		balance += flow
+		month.ClosingBalance = balance
+		if balance < month.LowestBalance {
+			month.LowestBalance = balance
+		}
+
+		if view.ZeroCashDate == nil && balance <= 0 {
+			view.ZeroCashDate = &date
+		}
+		if view.BelowFloorDate == nil && balance < floor {
+			view.BelowFloorDate = &date
+			view.BelowFloorMonth = &key
+		}
+	}
+	if month != nil {
+		view.Months = append(view.Months, roundMonth(*month))
+	}
+
+	return view
+}
+
+func roundMonth(m models.CashForecastMonth) models.CashForecastMonth {
+	m.OpeningBalance = roundCents(m.OpeningBalance)
+	m.Inflows = roundCents(m.Inflows)
+	m.Outflows = roundCents(m.Outflows)
+	m.ClosingBalance = roundCents(m.ClosingBalance)
+	m.LowestBalance = roundCents(m.LowestBalance)
+	return m
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func (s *forecastService) ListPlannedItems(agencyID string) ([]models.PlannedItemView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.plannedRepo.ListBetween(agencyID, ac.today(), "9999-12-31")
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.PlannedItemView, len(items))
+	for i, item := range items {
+		views[i] = toPlannedItemView(item)
+	}
+	return views, nil
+}
+
+func (s *forecastService) CreatePlannedItem(agencyID string, date string, amount float64, direction string, label string) (*models.PlannedItemView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if date <= ac.today() {
+		return nil, fmt.Errorf("%w: planned items must be dated after today; record past movements in the ledger", ErrInvalidEntryDate)
+	}
+
+	item, err := s.plannedRepo.Create(agencyID, date, amount, direction, label)
+	if err != nil {
+		return nil, err
+	}
+	view := toPlannedItemView(*item)
+	return &view, nil
+}
+
+func (s *forecastService) DeletePlannedItem(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrPlannedItemNotFound
+	}
+	deleted, err := s.plannedRepo.Delete(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPlannedItemNotFound
+	}
+	return nil
+}
+
+func toPlannedItemView(item repository.PlannedItemEntity) models.PlannedItemView {
+	return models.PlannedItemView{
+		ID:        item.ID,
+		Date:      item.Date,
+		Amount:    item.Amount,
+		Direction: item.Direction,
+		Label:     item.Label,
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+func TestCashForecastProjectsCostsEnteredByHand(t *testing.T) {
+	settings := repository.DefaultAgencySettings("")
+	// Rent of 9000 a month entered by hand, plus 3000 of variable spend
+	financeRepo := &fakeFinanceRepo{oneOffFixed: 27000, oneOffVariable: 9000}
+	svc := NewForecastService(
+		&fakeAgencyRepo{createdAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		&fakeBankRepo{balances: []repository.AccountBalanceEntity{{AccountID: "account-1", BaseBalance: 30000}}},
+		nil,
+		financeRepo,
+		&fakeRetainerRepo{},
+		&fakeRecurringCostRepo{},
+		&fakePlannedItemRepo{},
+		&fakeInvoiceRepo{},
+		&fakeSettingsRepo{settings: settings},
+		fixedAt(t, "2026-10-18T09:00:00Z"),
+	)
+
+	view, err := svc.GetCashForecast("agency-1", 3, false)
+	if err != nil {
+		t.Fatalf("GetCashForecast: %v", err)
+	}
+
+	wantOutflows := []float64{12000, 12000, 12000}
+	if len(view.Months) != len(wantOutflows) {
+		t.Fatalf("got %d months, want %d", len(view.Months), len(wantOutflows))
+	}
+	for i, m := range view.Months {
+		if m.Outflows != wantOutflows[i] {
+			t.Errorf("%s outflows = %v, want %v", m.Month, m.Outflows, wantOutflows[i])
+		}
+	}
+	if view.ZeroCashDate == nil || *view.ZeroCashDate != "2026-12-31" {
+		t.Errorf("zero cash date = %v, want 2026-12-31", view.ZeroCashDate)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	ErrInvalidInvoice  = errors.New("invalid invoice")
+)
+
+type InvoiceInput struct {
+	ClientID *string
+	Number   string
+	Amount   float64
+	DueDate  string
+}
+
+// InvoiceUpdate is a partial update; nil fields are left unchanged. Setting
+// PaidOn marks the invoice collected and an empty PaidOn reopens it.
+type InvoiceUpdate struct {
+	Number  *string
+	Amount  *float64
+	DueDate *string
+	PaidOn  *string
+}
+
+type InvoiceService interface {
+	ListInvoices(agencyID string, outstandingOnly bool) ([]models.InvoiceView, error)
+	CreateInvoice(agencyID string, input InvoiceInput) (*models.InvoiceView, error)
+	UpdateInvoice(agencyID string, id string, update InvoiceUpdate) (*models.InvoiceView, error)
+	DeleteInvoice(agencyID string, id string) error
+}
+
+type invoiceService struct {
+	invoiceRepo repository.InvoiceRepository
+	clientRepo  repository.ClientRepository
+}
+
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, clientRepo repository.ClientRepository) InvoiceService {
+	return &invoiceService{
+		invoiceRepo: invoiceRepo,
+		clientRepo:  clientRepo,
+	}
+}
+
+func (s *invoiceService) ListInvoices(agencyID string, outstandingOnly bool) ([]models.InvoiceView, error) {
+	invoices, err := s.invoiceRepo.List(agencyID, outstandingOnly)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.InvoiceView, len(invoices))
+	for i, inv := range invoices {
+		views[i] = toInvoiceView(inv)
+	}
+	return views, nil
+}
+
+func (s *invoiceService) CreateInvoice(agencyID string, input InvoiceInput) (*models.InvoiceView, error) {
+	if input.ClientID != nil {
+		if _, err := uuid.Parse(*input.ClientID); err != nil {
+			return nil, fmt.Errorf("%w: unknown client %s", ErrInvalidInvoice, *input.ClientID)
+		}
+		client, err := s.clientRepo.Get(agencyID, *input.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			return nil, fmt.Errorf("%w: unknown client %s", ErrInvalidInvoice, *input.ClientID)
+		}
+	}
+
+	inv, err := s.invoiceRepo.Create(repository.InvoiceEntity{
+		AgencyID: agencyID,
+		ClientID: input.ClientID,
+		Number:   input.Number,
+		Amount:   input.Amount,
+		DueDate:  input.DueDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	view := toInvoiceView(*inv)
+	return &view, nil
+}
+
+func (s *invoiceService) UpdateInvoice(agencyID string, id string, update InvoiceUpdate) (*models.InvoiceView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	inv, err := s.invoiceRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	if update.Number != nil {
+		inv.Number = *update.Number
+	}
+	if update.Amount != nil {
+		inv.Amount = *update.Amount
+	}
+	if update.DueDate != nil {
+		inv.DueDate = *update.DueDate
+	}
+	if update.PaidOn != nil {
+		if *update.PaidOn == "" {
+			inv.PaidOn = nil
+		} else {
+			inv.PaidOn = update.PaidOn
+		}
+	}
+
+	if err := s.invoiceRepo.Update(*inv); err != nil {
+		return nil, err
+	}
+	view := toInvoiceView(*inv)
+	return &view, nil
+}
+
+func (s *invoiceService) DeleteInvoice(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrInvoiceNotFound
+	}
+	deleted, err := s.invoiceRepo.Delete(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInvoiceNotFound
+	}
+	return nil
+}
+
+func toInvoiceView(inv repository.InvoiceEntity) models.InvoiceView {
+	return models.InvoiceView{
+		ID:       inv.ID,
+		ClientID: inv.ClientID,
+		Number:   inv.Number,
+		Amount:   inv.Amount,
+		DueDate:  inv.DueDate,
+		PaidOn:   inv.PaidOn,
+	}
+}
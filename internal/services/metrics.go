@@ -0,0 +1,307 @@
+package services
+
+import (
+	"math"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+// metricsInputs are the measurements survival metrics and the reality score
+// are computed from. Gathering them is separate from scoring so scenarios can
+// adjust the inputs without touching stored data.
+type metricsInputs struct {
+	// position is nil when no cash has been recorded
+	position  *cashPosition
+	burn      burnFigures
+	retainers []repository.RetainerEntity
+	// revenue, costs, usedHours and capacityHours cover the lookback window
+	revenue       float64
+	costs         float64
+	usedHours     float64
+	capacityHours float64
+	// windowMonths scales monthly hours to the lookback window
+	windowMonths float64
+}
+
+func (in *metricsInputs) totalRetainer() float64 {
+	var total float64
+	for _, r := range in.retainers {
+		total += r.MonthlyAmount
+	}
+	return total
+}
+
+func (in *metricsInputs) maxRetainer() float64 {
+	var max float64
+	for _, r := range in.retainers {
+		if r.MonthlyAmount > max {
+			max = r.MonthlyAmount
+		}
+	}
+	return max
+}
+
+func (in *metricsInputs) view() models.ScoreInputsView {
+	v := models.ScoreInputsView{
+		MonthlyBurn:     in.burn.monthly(),
+		TotalRetainers:  in.totalRetainer(),
+		LargestRetainer: in.maxRetainer(),
+		Revenue:         in.revenue,
+		Costs:           in.costs,
+		UsedHours:       in.usedHours,
+		CapacityHours:   in.capacityHours,
+	}
+	if in.position != nil {
+		cash := in.position.total
+		v.CashOnHand = &cash
+	}
+	return v
+}
+
+// missingInputs names the data a meaningful score needs but the agency
+// hasn't recorded: a cash balance, any costs, and time entries in the
+// lookback window.
+func (in *metricsInputs) missingInputs() []string {
+	missing := []string{}
+	if in.position == nil {
+		missing = append(missing, "cash_snapshot")
+	}
+	if in.burn.monthly() == 0 && in.costs == 0 {
+		missing = append(missing, "costs")
+	}
+	if in.usedHours == 0 {
+		missing = append(missing, "time_entries")
+	}
+	return missing
+}
+
+// Names of the measurements the reality score is built from, as reported in
+// its components and watched by alert rules.
+const (
+	MeasureRetainerCoverage   = "retainer_coverage_ratio"
+	MeasureRunwayMonths       = "runway_months"
+	MeasureTopClientPercent   = "top_client_percent"
+	MeasureMarginPercent      = "margin_percent"
+	MeasureUtilizationPercent = "utilization_percent"
+)
+
+// scoreMeasurements holds the value behind each score component. Each is nil
+// when it can't be taken, scoring zero.
+type scoreMeasurements struct {
+	coverage, runway, concentration, margin, utilization *float64
+}
+
+func (in *metricsInputs) measurements() scoreMeasurements {
+	var m scoreMeasurements
+	totalRetainer := in.totalRetainer()
+	fixedCosts := in.burn.monthly()
+
+	// A. Retainer Safety
+	if fixedCosts > 0 {
+		m.coverage = measured(totalRetainer / fixedCosts)
+	}
+
+	// B. Runway Health
+	if in.position != nil && fixedCosts > 0 {
+		m.runway = measured(in.position.total / fixedCosts)
+	}
+
+	// C. Client Concentration
+	if totalRetainer > 0 {
+		m.concentration = measured((in.maxRetainer() / totalRetainer) * 100)
+	}
+
+	// D. Profitability - rolling lookback window (Ticket 09 fix)
+	if in.revenue > 0 {
+		m.margin = measured(((in.revenue - in.costs) / in.revenue) * 100)
+	}
+
+	// E. Capacity Pressure
+	m.utilization = measured((in.usedHours / in.capacityHours) * 100)
+	return m
+}
+
+// value looks a measurement up by name.
+func (m scoreMeasurements) value(measure string) *float64 {
+	switch measure {
+	case MeasureRetainerCoverage:
+		return m.coverage
+	case MeasureRunwayMonths:
+		return m.runway
+	case MeasureTopClientPercent:
+		return m.concentration
+	case MeasureMarginPercent:
+		return m.margin
+	case MeasureUtilizationPercent:
+		return m.utilization
+	}
+	return nil
+}
+
+type metricsLoader struct {
+	bankRepo     repository.BankAccountRepository
+	cashRepo     repository.CashSnapshotRepository
+	financeRepo  repository.FinanceRepository
+	retainerRepo repository.RetainerRepository
+	timeRepo     repository.TimeEntryRepository
+	burn         burnCalculator
+}
+
+func (l metricsLoader) load(ac *agencyContext, agencyID string) (*metricsInputs, error) {
+	windowStart := ac.windowStart()
+	in := &metricsInputs{
+		capacityHours: ac.settings.MonthlyCapacityHours * ac.windowMonths(),
+		windowMonths:  ac.windowMonths(),
+	}
+	var err error
+
+	if in.position, err = currentCashPosition(l.bankRepo, l.cashRepo, agencyID, ac.today()); err != nil {
+		return nil, err
+	}
+	figures, err := l.burn.compute(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	in.burn = *figures
+	if in.retainers, err = l.retainerRepo.ListActive(agencyID); err != nil {
+		return nil, err
+	}
+	if in.revenue, err = l.financeRepo.SumAllRevenuesInRange(agencyID, windowStart); err != nil {
+		return nil, err
+	}
+	if in.costs, err = l.financeRepo.SumAllCostsInRange(agencyID, windowStart); err != nil {
+		return nil, err
+	}
+	if in.usedHours, err = l.timeRepo.SumHoursInRange(agencyID, windowStart); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// computeSurvival returns nil when no cash has been recorded.
+func computeSurvival(in *metricsInputs, excludeRestricted bool) *models.SurvivalMetricsView {
+	if in.position == nil {
+		return nil
+	}
+	burn := in.burn.monthly()
+	retainers := in.totalRetainer()
+
+	view := &models.SurvivalMetricsView{
+		CashBalance:        in.position.total,
+		RestrictedCash:     in.position.restricted,
+		AvailableCash:      in.position.available(),
+		ExcludesRestricted: excludeRestricted,
+		MonthlyBurn:        burn,
+		RecurringBurn:      in.burn.recurring,
+		AverageOneOffBurn:  math.Round(in.burn.oneOffAverage*100) / 100,
+		BurnAverageMonths:  in.burn.months,
+		RawMonthlyBurn:     in.burn.raw,
+		TotalRetainers:     retainers,
+		OperatingMargin:    retainers - burn,
+	}
+
+	cash := in.position.total
+	if excludeRestricted {
+		cash = in.position.available()
+	}
+	if burn > 0 {
+		runway := cash / burn
+		runway = float64(int(runway*10)) / 10
+		view.RunwayMonths = &runway
+	}
+
+	return view
+}
+
+func computeRealityScore(in *metricsInputs, sc *scoring) *models.RealityScoreView {
+	result := models.RealityScoreView{ProfileVersion: sc.version}
+	p := sc.profile
+	totalRetainer := in.totalRetainer()
+	fixedCosts := in.burn.monthly()
+	var cash *float64
+	if in.position != nil {
+		cash = &in.position.total
+	}
+
+	m := in.measurements()
+	components := []componentScore{
+		scoreComponent("retainer_safety", MeasureRetainerCoverage, p.RetainerSafety, m.coverage),
+		scoreComponent("runway", MeasureRunwayMonths, p.Runway, m.runway),
+		scoreComponent("client_concentration", MeasureTopClientPercent, p.ClientConcentration, m.concentration),
+		scoreComponent("profitability", MeasureMarginPercent, p.Profitability, m.margin),
+		scoreComponent("capacity_pressure", MeasureUtilizationPercent, p.CapacityPressure, m.utilization),
+	}
+	for _, c := range components {
+		result.Components = append(result.Components, c.view)
+	}
+	result.Breakdown.RetainerSafety = components[0].view.Points
+	result.Breakdown.Runway = components[1].view.Points
+	result.Breakdown.ClientConcentration = components[2].view.Points
+	result.Breakdown.Profitability = components[3].view.Points
+	result.Breakdown.CapacityPressure = components[4].view.Points
+	result.Actions = scoreActions(in, components)
+
+	result.Score = result.Breakdown.RetainerSafety + result.Breakdown.Runway + result.Breakdown.ClientConcentration + result.Breakdown.Profitability + result.Breakdown.CapacityPressure
+	result.Status = sc.status(result.Score)
+
+	if cash != nil {
+		result.CashOnHand = *cash
+	}
+	result.CommittedRetainers = totalRetainer
+
+	// Without cash, costs or time the bands above can't tell a healthy agency
+	// from an empty one
+	result.MissingInputs = in.missingInputs()
+	if len(result.MissingInputs) > 0 {
+		result.Status = StatusInsufficientData
+		result.PrimaryRisk = "Insufficient Data"
+		return &result
+	}
+
+	// Primary Risk Attribution (Ticket 11)
+	if result.Score >= p.StatusCutoffs.Healthy {
+		result.PrimaryRisk = "Healthy"
+	} else {
+		// Priority order
+		if fixedCosts > totalRetainer && fixedCosts > 0 {
+			result.PrimaryRisk = "High Fixed Costs"
+		} else if fixedCosts > 0 && totalRetainer/fixedCosts < 1.0 {
+			result.PrimaryRisk = "Low Retainer Base"
+		} else {
+			topPct := 0.0
+			if totalRetainer > 0 {
+				topPct = (in.maxRetainer() / totalRetainer) * 100
+			}
+			if topPct > 60 {
+				result.PrimaryRisk = "Client Concentration"
+			} else if cash != nil && fixedCosts > 0 && (*cash/fixedCosts) < 2 {
+				result.PrimaryRisk = "Low Runway"
+			} else {
+				// None of the named risks applies, so blame the component
+				// furthest from full marks
+				result.PrimaryRisk = weakestComponentRisk(result.Components)
+			}
+		}
+	}
+
+	return &result
+}
+
+var componentRisks = map[string]string{
+	"retainer_safety":      "Low Retainer Base",
+	"runway":               "Low Runway",
+	"client_concentration": "Client Concentration",
+	"profitability":        "Low Profitability",
+	"capacity_pressure":    "Capacity Pressure",
+}
+
+func weakestComponentRisk(components []models.ScoreComponentView) string {
+	risk, shortfall := "Healthy", 0
+	for _, c := range components {
+		if gap := c.PointsAvailable - c.Points; gap > shortfall {
+			risk, shortfall = componentRisks[c.Component], gap
+		}
+	}
+	return risk
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRecurringCostNotFound = errors.New("recurring cost not found")
+	ErrInvalidRecurringCost  = errors.New("invalid recurring cost")
+)
+
+// maxPreviewDays bounds how far ahead upcoming postings can be listed.
+const maxPreviewDays = 366
+
+type RecurringCostInput struct {
+	Amount    float64
+	Label     string
+	Category  string
+	Cadence   string
+	StartDate string
+	EndDate   *string
+}
+
+// RecurringCostUpdate is a partial update; nil fields are left unchanged and
+// an empty EndDate removes the end date. Changes apply to future postings only.
+type RecurringCostUpdate struct {
+	Amount   *float64
+	Label    *string
+	Category *string
+	EndDate  *string
+}
+
+type RecurringCostService interface {
+	ListRecurringCosts(agencyID string) ([]models.RecurringCostView, error)
+	CreateRecurringCost(agencyID string, input RecurringCostInput) (*models.RecurringCostView, error)
+	UpdateRecurringCost(agencyID string, id string, update RecurringCostUpdate) (*models.RecurringCostView, error)
+	DeleteRecurringCost(agencyID string, id string) error
+	ListUpcoming(agencyID string, days int) ([]models.UpcomingCostView, error)
+	PostDueCosts() error
+}
+
+type recurringCostService struct {
+	agencyRepo    repository.AgencyRepository
+	recurringRepo repository.RecurringCostRepository
+	financeRepo   repository.FinanceRepository
+	settingsRepo  repository.SettingsRepository
+	events        publisher
+	clock         clock.Clock
+}
+
+func NewRecurringCostService(
+	agencyRepo repository.AgencyRepository,
+	recurringRepo repository.RecurringCostRepository,
+	financeRepo repository.FinanceRepository,
+	settingsRepo repository.SettingsRepository,
+	bus *events.Bus,
+	clk clock.Clock,
+) RecurringCostService {
+	return &recurringCostService{
+		agencyRepo:    agencyRepo,
+		recurringRepo: recurringRepo,
+		financeRepo:   financeRepo,
+		settingsRepo:  settingsRepo,
+		events:        publisher{bus: bus, clock: clk},
+		clock:         clk,
+	}
+}
+
+func (s *recurringCostService) ListRecurringCosts(agencyID string) ([]models.RecurringCostView, error) {
+	costs, err := s.recurringRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.RecurringCostView, len(costs))
+	for i, c := range costs {
+		views[i] = toRecurringCostView(c)
+	}
+	return views, nil
+}
+
+// CreateRecurringCost saves the template and immediately posts anything
+// already due, so a start date in the past is backfilled.
+func (s *recurringCostService) CreateRecurringCost(agencyID string, input RecurringCostInput) (*models.RecurringCostView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	earliest := agency.CreatedAt.In(ac.now.Location()).Format("2006-01-02")
+	if input.StartDate < earliest {
+		return nil, fmt.Errorf("%w: start_date is before the agency was created", ErrInvalidRecurringCost)
+	}
+	if input.EndDate != nil && *input.EndDate < input.StartDate {
+		return nil, fmt.Errorf("%w: end_date is before start_date", ErrInvalidRecurringCost)
+	}
+
+	cost, err := s.recurringRepo.Create(repository.RecurringCostEntity{
+		AgencyID:  agencyID,
+		Amount:    input.Amount,
+		Label:     input.Label,
+		Category:  input.Category,
+		Cadence:   input.Cadence,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postDue(cost, ac.today()); err != nil {
+		return nil, err
+	}
+	view := toRecurringCostView(*cost)
+	s.events.publish(events.RecurringCostChanged, agencyID, view)
+	return &view, nil
+}
+
+func (s *recurringCostService) UpdateRecurringCost(agencyID string, id string, update RecurringCostUpdate) (*models.RecurringCostView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrRecurringCostNotFound
+	}
+	cost, err := s.recurringRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cost == nil {
+		return nil, ErrRecurringCostNotFound
+	}
+
+	if update.Amount != nil {
+		cost.Amount = *update.Amount
+	}
+	if update.Label != nil {
+		cost.Label = *update.Label
+	}
+	if update.Category != nil {
+		cost.Category = *update.Category
+	}
+	if update.EndDate != nil {
+		if *update.EndDate == "" {
+			cost.EndDate = nil
+		} else if *update.EndDate < cost.StartDate {
+			return nil, fmt.Errorf("%w: end_date is before start_date", ErrInvalidRecurringCost)
+		} else {
+			cost.EndDate = update.EndDate
+		}
+	}
+
+	if err := s.recurringRepo.Update(*cost); err != nil {
+		return nil, err
+	}
+	view := toRecurringCostView(*cost)
+	s.events.publish(events.RecurringCostChanged, agencyID, view)
+	return &view, nil
+}
+
+func (s *recurringCostService) DeleteRecurringCost(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrRecurringCostNotFound
+	}
+	archived, err := s.recurringRepo.Archive(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !archived {
+		return ErrRecurringCostNotFound
+	}
+	s.events.publish(events.RecurringCostChanged, agencyID, models.DeletedView{ID: id})
+	return nil
+}
+
+// ListUpcoming lists postings due from the day after each template's last
+// posting through the given number of days ahead, including any that are due
+// but not yet picked up by the scheduler.
+func (s *recurringCostService) ListUpcoming(agencyID string, days int) ([]models.UpcomingCostView, error) {
+	if days < 1 || days > maxPreviewDays {
+		return nil, fmt.Errorf("%w: days must be 1-%d", ErrInvalidDateRange, maxPreviewDays)
+	}
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	until := ac.now.AddDate(0, 0, days).Format("2006-01-02")
+
+	costs, err := s.recurringRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []models.UpcomingCostView{}
+	for _, c := range costs {
+		for _, date := range occurrences(c, nextUnposted(c), until) {
+			upcoming = append(upcoming, models.UpcomingCostView{
+				RecurringCostID: c.ID,
+				Date:            date,
+				Amount:          c.Amount,
+				Label:           c.Label,
+				Category:        c.Category,
+			})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date < upcoming[j].Date
+	})
+	return upcoming, nil
+}
+
+// PostDueCosts materializes every template's postings up to today in its
+// agency's timezone. It runs from the scheduler and is safe to repeat.
+func (s *recurringCostService) PostDueCosts() error {
+	costs, err := s.recurringRepo.ListActive()
+	if err != nil {
+		return err
+	}
+
+	today := make(map[string]string)
+	var failed []error
+	for i := range costs {
+		c := &costs[i]
+		if _, ok := today[c.AgencyID]; !ok {
+			ac, err := loadAgencyContext(s.settingsRepo, s.clock, c.AgencyID)
+			if err != nil {
+				failed = append(failed, err)
+				continue
+			}
+			today[c.AgencyID] = ac.today()
+		}
+		if err := s.postDue(c, today[c.AgencyID]); err != nil {
+			failed = append(failed, fmt.Errorf("recurring cost %s: %w", c.ID, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+func (s *recurringCostService) postDue(c *repository.RecurringCostEntity, today string) error {
+	through := today
+	if c.EndDate != nil && *c.EndDate < through {
+		through = *c.EndDate
+	}
+	dates := occurrences(*c, nextUnposted(*c), through)
+	posted, err := s.recurringRepo.Post(*c, dates, through)
+	if err != nil {
+		return err
+	}
+	if c.PostedThrough == nil || *c.PostedThrough < through {
+		c.PostedThrough = &through
+	}
+	for _, id := range posted {
+		if err := publishCostCreated(s.financeRepo, s.events, c.AgencyID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextUnposted is the first date the scheduler has not yet covered.
+func nextUnposted(c repository.RecurringCostEntity) string {
+	if c.PostedThrough == nil {
+		return c.StartDate
+	}
+	d, _ := time.Parse("2006-01-02", *c.PostedThrough)
+	next := d.AddDate(0, 0, 1).Format("2006-01-02")
+	if next < c.StartDate {
+		return c.StartDate
+	}
+	return next
+}
+
+// occurrences lists the template's posting dates between from and to
+// inclusive. Monthly and annual postings keep the start date's day of month,
+// falling back to the last day of shorter months.
+func occurrences(c repository.RecurringCostEntity, from string, to string) []string {
+	if c.EndDate != nil && *c.EndDate < to {
+		to = *c.EndDate
+	}
+	start, err := time.Parse("2006-01-02", c.StartDate)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	for n := 0; ; n++ {
+		var d time.Time
+		switch c.Cadence {
+		case "weekly":
+			d = start.AddDate(0, 0, 7*n)
+		case "annual":
+			d = addMonthsClamped(start, 12*n)
+		default:
+			d = addMonthsClamped(start, n)
+		}
+		date := d.Format("2006-01-02")
+		if date > to {
+			break
+		}
+		if date >= from {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, t.Location())
+}
+
+func toRecurringCostView(c repository.RecurringCostEntity) models.RecurringCostView {
+	view := models.RecurringCostView{
+		ID:            c.ID,
+		Amount:        c.Amount,
+		Label:         c.Label,
+		Category:      c.Category,
+		Cadence:       c.Cadence,
+		StartDate:     c.StartDate,
+		EndDate:       c.EndDate,
+		PostedThrough: c.PostedThrough,
+	}
+	// Look a little over a year ahead so annual templates always find one
+	from := nextUnposted(c)
+	fromDate, _ := time.Parse("2006-01-02", from)
+	if next := occurrences(c, from, fromDate.AddDate(1, 0, 1).Format("2006-01-02")); len(next) > 0 {
+		view.NextDate = &next[0]
+	}
+	return view
+}
@@ -0,0 +1,408 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrScenarioNotFound = errors.New("scenario not found")
+	ErrInvalidScenario  = errors.New("invalid scenario")
+)
+
+const (
+	AdjustmentAddHire     = "add_hire"
+	AdjustmentLoseClient  = "lose_client"
+	AdjustmentAddRetainer = "add_retainer"
+	AdjustmentCutCost     = "cut_cost"
+)
+
+// ScenarioAdjustment is one hypothetical change. Which optional fields are
+// needed depends on Kind.
+type ScenarioAdjustment struct {
+	Kind            string
+	Label           string
+	Amount          *float64
+	CapacityHours   *float64
+	ClientID        *string
+	RecurringCostID *string
+	StartDate       string
+}
+
+type ScenarioInput struct {
+	Name        string
+	Description string
+	Adjustments []ScenarioAdjustment
+}
+
+func (in ScenarioInput) adjustments() []repository.ScenarioAdjustmentEntity {
+	entities := make([]repository.ScenarioAdjustmentEntity, len(in.Adjustments))
+	for i, a := range in.Adjustments {
+		entities[i] = repository.ScenarioAdjustmentEntity(a)
+	}
+	return entities
+}
+
+type ScenarioService interface {
+	ListScenarios(agencyID string) ([]models.ScenarioView, error)
+	GetScenario(agencyID string, id string) (*models.ScenarioView, error)
+	CreateScenario(agencyID string, userID string, input ScenarioInput) (*models.ScenarioView, error)
+	UpdateScenario(agencyID string, id string, input ScenarioInput) (*models.ScenarioView, error)
+	DeleteScenario(agencyID string, id string) error
+	EvaluateScenario(agencyID string, id string, months int, excludeRestricted bool) (*models.ScenarioEvaluationView, error)
+}
+
+type scenarioService struct {
+	scenarioRepo  repository.ScenarioRepository
+	clientRepo    repository.ClientRepository
+	recurringRepo repository.RecurringCostRepository
+	settingsRepo  repository.SettingsRepository
+	scoringRepo   repository.ScoringProfileRepository
+	metrics       metricsLoader
+	inputs        forecastLoader
+	clock         clock.Clock
+}
+
+func NewScenarioService(
+	scenarioRepo repository.ScenarioRepository,
+	clientRepo repository.ClientRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	financeRepo repository.FinanceRepository,
+	recurringRepo repository.RecurringCostRepository,
+	retainerRepo repository.RetainerRepository,
+	plannedRepo repository.PlannedItemRepository,
+	invoiceRepo repository.InvoiceRepository,
+	timeRepo repository.TimeEntryRepository,
+	settingsRepo repository.SettingsRepository,
+	scoringRepo repository.ScoringProfileRepository,
+	clk clock.Clock,
+) ScenarioService {
+	return &scenarioService{
+		scenarioRepo:  scenarioRepo,
+		clientRepo:    clientRepo,
+		recurringRepo: recurringRepo,
+		settingsRepo:  settingsRepo,
+		scoringRepo:   scoringRepo,
+		metrics: metricsLoader{
+			bankRepo:     bankRepo,
+			cashRepo:     cashRepo,
+			financeRepo:  financeRepo,
+			retainerRepo: retainerRepo,
+			timeRepo:     timeRepo,
+			burn:         burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		inputs: forecastLoader{
+			retainerRepo:  retainerRepo,
+			recurringRepo: recurringRepo,
+			plannedRepo:   plannedRepo,
+			invoiceRepo:   invoiceRepo,
+			burn:          burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		clock: clk,
+	}
+}
+
+func (s *scenarioService) ListScenarios(agencyID string) ([]models.ScenarioView, error) {
+	scenarios, err := s.scenarioRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.ScenarioView, len(scenarios))
+	for i, sc := range scenarios {
+		views[i] = toScenarioView(sc)
+	}
+	return views, nil
+}
+
+func (s *scenarioService) GetScenario(agencyID string, id string) (*models.ScenarioView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrScenarioNotFound
+	}
+	scenario, err := s.scenarioRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario == nil {
+		return nil, ErrScenarioNotFound
+	}
+	view := toScenarioView(*scenario)
+	return &view, nil
+}
+
+func (s *scenarioService) CreateScenario(agencyID string, userID string, input ScenarioInput) (*models.ScenarioView, error) {
+	adjustments := input.adjustments()
+	if err := s.validate(agencyID, adjustments); err != nil {
+		return nil, err
+	}
+	scenario, err := s.scenarioRepo.Create(repository.ScenarioEntity{
+		AgencyID:    agencyID,
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedBy:   userID,
+		Adjustments: adjustments,
+	})
+	if err != nil {
+		return nil, err
+	}
+	view := toScenarioView(*scenario)
+	return &view, nil
+}
+
+func (s *scenarioService) UpdateScenario(agencyID string, id string, input ScenarioInput) (*models.ScenarioView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrScenarioNotFound
+	}
+	existing, err := s.scenarioRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrScenarioNotFound
+	}
+	adjustments := input.adjustments()
+	if err := s.validate(agencyID, adjustments); err != nil {
+		return nil, err
+	}
+
+	existing.Name = input.Name
+	existing.Description = input.Description
+	existing.Adjustments = adjustments
+	if err := s.scenarioRepo.Replace(*existing); err != nil {
+		return nil, err
+	}
+	return s.GetScenario(agencyID, id)
+}
+
+func (s *scenarioService) DeleteScenario(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrScenarioNotFound
+	}
+	deleted, err := s.scenarioRepo.Delete(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScenarioNotFound
+	}
+	return nil
+}
+
+// validate checks each adjustment has the fields its kind needs and that
+// referenced clients and recurring costs belong to the agency.
+func (s *scenarioService) validate(agencyID string, adjustments []repository.ScenarioAdjustmentEntity) error {
+	var activeClients map[string]bool
+	for i, a := range adjustments {
+		if _, err := time.Parse("2006-01-02", a.StartDate); err != nil {
+			return fmt.Errorf("%w: adjustment %d: start_date must be YYYY-MM-DD", ErrInvalidScenario, i+1)
+		}
+
+		switch a.Kind {
+		case AdjustmentAddHire, AdjustmentAddRetainer:
+			if a.Amount == nil || *a.Amount <= 0 {
+				return fmt.Errorf("%w: adjustment %d: %s needs a positive amount", ErrInvalidScenario, i+1, a.Kind)
+			}
+		case AdjustmentLoseClient:
+			if a.ClientID == nil {
+				return fmt.Errorf("%w: adjustment %d: lose_client needs client_id", ErrInvalidScenario, i+1)
+			}
+			if activeClients == nil {
+				clients, err := s.clientRepo.GetAllActive(agencyID)
+				if err != nil {
+					return err
+				}
+				activeClients = make(map[string]bool, len(clients))
+				for _, c := range clients {
+					activeClients[c.ID] = true
+				}
+			}
+			if !activeClients[*a.ClientID] {
+				return fmt.Errorf("%w: adjustment %d: unknown client %s", ErrInvalidScenario, i+1, *a.ClientID)
+			}
+		case AdjustmentCutCost:
+			if a.RecurringCostID == nil {
+				return fmt.Errorf("%w: adjustment %d: cut_cost needs recurring_cost_id", ErrInvalidScenario, i+1)
+			}
+			cost, err := s.recurringRepo.Get(agencyID, *a.RecurringCostID)
+			if err != nil {
+				return err
+			}
+			if cost == nil {
+				return fmt.Errorf("%w: adjustment %d: unknown recurring cost %s", ErrInvalidScenario, i+1, *a.RecurringCostID)
+			}
+		default:
+			return fmt.Errorf("%w: adjustment %d: unknown kind %q", ErrInvalidScenario, i+1, a.Kind)
+		}
+	}
+	return nil
+}
+
+// EvaluateScenario recalculates survival metrics, the cash forecast and the
+// reality score with the scenario's adjustments applied to in-memory copies of
+// the agency's inputs. The forecast honours each adjustment's start date;
+// survival metrics and the score show the position once every adjustment is
+// in effect.
+func (s *scenarioService) EvaluateScenario(agencyID string, id string, months int, excludeRestricted bool) (*models.ScenarioEvaluationView, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrScenarioNotFound
+	}
+	if months < 1 || months > maxForecastMonths {
+		return nil, fmt.Errorf("%w: months must be 1-%d", ErrInvalidDateRange, maxForecastMonths)
+	}
+	scenario, err := s.scenarioRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario == nil {
+		return nil, ErrScenarioNotFound
+	}
+
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.metrics.load(ac, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := loadScoring(s.scoringRepo, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	today, end := forecastWindow(ac, months)
+	tomorrow := today.AddDate(0, 0, 1)
+	baseFlows, err := s.inputs.load(ac, agencyID, tomorrow, end)
+	if err != nil {
+		return nil, err
+	}
+
+	projected := applyToMetrics(*base, scenario.Adjustments, baseFlows.recurring, ac.today())
+	projectedFlows := applyToForecast(*baseFlows, scenario.Adjustments)
+
+	view := &models.ScenarioEvaluationView{
+		Scenario: toScenarioView(*scenario),
+		Baseline: models.ScenarioOutcomeView{
+			Survival:     computeSurvival(base, excludeRestricted),
+			RealityScore: computeRealityScore(base, sc),
+		},
+		Projected: models.ScenarioOutcomeView{
+			Survival:     computeSurvival(&projected, excludeRestricted),
+			RealityScore: computeRealityScore(&projected, sc),
+		},
+	}
+
+	if base.position != nil {
+		balance := base.position.total
+		if excludeRestricted {
+			balance = base.position.available()
+		}
+		floor := ac.settings.CashSafetyFloor
+		view.Baseline.Forecast = projectCash(balance, today, end, baseFlows.flows(tomorrow, end), floor)
+		view.Baseline.Forecast.ExcludesRestricted = excludeRestricted
+		view.Projected.Forecast = projectCash(balance, today, end, projectedFlows.flows(tomorrow, end), floor)
+		view.Projected.Forecast.ExcludesRestricted = excludeRestricted
+	}
+
+	return view, nil
+}
+
+// applyToMetrics returns a copy of in with every adjustment in effect.
+func applyToMetrics(in metricsInputs, adjustments []repository.ScenarioAdjustmentEntity, recurring []repository.RecurringCostEntity, today string) metricsInputs {
+	in.retainers = append([]repository.RetainerEntity(nil), in.retainers...)
+
+	for _, a := range adjustments {
+		switch a.Kind {
+		case AdjustmentAddHire:
+			in.burn.recurring += *a.Amount
+			if a.CapacityHours != nil {
+				in.capacityHours += *a.CapacityHours * in.windowMonths
+			}
+		case AdjustmentAddRetainer:
+			in.retainers = append(in.retainers, repository.RetainerEntity{MonthlyAmount: *a.Amount})
+		case AdjustmentLoseClient:
+			kept := in.retainers[:0]
+			for _, r := range in.retainers {
+				if r.ClientID != *a.ClientID {
+					kept = append(kept, r)
+				}
+			}
+			in.retainers = kept
+		case AdjustmentCutCost:
+			for _, t := range recurring {
+				active := t.StartDate <= today && (t.EndDate == nil || *t.EndDate >= today)
+				if t.ID == *a.RecurringCostID && active {
+					in.burn.recurring -= monthlyEquivalent(t)
+				}
+			}
+		}
+	}
+	return in
+}
+
+// applyToForecast returns a copy of in with each adjustment taking effect on
+// its start date.
+func applyToForecast(in forecastInputs, adjustments []repository.ScenarioAdjustmentEntity) forecastInputs {
+	in.retainers = append([]projectedRetainer(nil), in.retainers...)
+	in.recurring = append([]repository.RecurringCostEntity(nil), in.recurring...)
+
+	for _, a := range adjustments {
+		switch a.Kind {
+		case AdjustmentAddHire:
+			in.recurring = append(in.recurring, repository.RecurringCostEntity{
+				Amount:    *a.Amount,
+				Label:     a.Label,
+				Category:  "people",
+				Cadence:   "monthly",
+				StartDate: a.StartDate,
+			})
+		case AdjustmentAddRetainer:
+			in.retainers = append(in.retainers, projectedRetainer{amount: *a.Amount, billingDay: 1, from: a.StartDate})
+		case AdjustmentLoseClient:
+			for i := range in.retainers {
+				r := &in.retainers[i]
+				if r.clientID == *a.ClientID && (r.until == "" || a.StartDate < r.until) {
+					r.until = a.StartDate
+				}
+			}
+		case AdjustmentCutCost:
+			start, _ := time.Parse("2006-01-02", a.StartDate)
+			lastDay := start.AddDate(0, 0, -1).Format("2006-01-02")
+			for i := range in.recurring {
+				t := &in.recurring[i]
+				if t.ID == *a.RecurringCostID && (t.EndDate == nil || lastDay < *t.EndDate) {
+					t.EndDate = &lastDay
+				}
+			}
+		}
+	}
+	return in
+}
+
+func toScenarioView(s repository.ScenarioEntity) models.ScenarioView {
+	view := models.ScenarioView{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+		Adjustments: make([]models.ScenarioAdjustmentView, len(s.Adjustments)),
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+	for i, a := range s.Adjustments {
+		view.Adjustments[i] = models.ScenarioAdjustmentView{
+			Kind:            a.Kind,
+			Label:           a.Label,
+			Amount:          a.Amount,
+			CapacityHours:   a.CapacityHours,
+			ClientID:        a.ClientID,
+			RecurringCostID: a.RecurringCostID,
+			StartDate:       a.StartDate,
+		}
+	}
+	return view
+}
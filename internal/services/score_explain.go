@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/agency-finance-reality/server/internal/models"
+)
+
+// componentScore is a scored component along with the exact value its next
+// band starts at, which actions are sized from.
+type componentScore struct {
+	view   models.ScoreComponentView
+	value  *float64
+	target *float64
+}
+
+// measured returns nil for values that can't be scored, such as a
+// utilization computed against zero capacity.
+func measured(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func scoreComponent(name string, measure string, c models.ScoreComponentProfile, value *float64) componentScore {
+	cs := componentScore{
+		view: models.ScoreComponentView{
+			Component:       name,
+			Measure:         measure,
+			PointsAvailable: c.Weight,
+		},
+		value: value,
+	}
+	if value == nil {
+		return cs
+	}
+	v := *value
+	rounded := math.Round(v*100) / 100
+	cs.view.Value = &rounded
+
+	for i := range c.Bands {
+		if bandContains(c.Bands[i], v) {
+			cs.view.Band = &c.Bands[i]
+			cs.view.Points = c.Bands[i].Points
+			break
+		}
+	}
+
+	// The closest band worth more, preferring more points when equally close
+	var best float64
+	for i := range c.Bands {
+		b := c.Bands[i]
+		if b.Points <= cs.view.Points {
+			continue
+		}
+		target := bandTarget(b, v)
+		distance := math.Abs(target - v)
+		if cs.view.NextBand == nil || distance < best || (distance == best && b.Points > cs.view.NextBand.Points) {
+			cs.view.NextBand = &c.Bands[i]
+			cs.target = &target
+			best = distance
+		}
+	}
+	if cs.target != nil {
+		delta := roundAway(*cs.target - v)
+		cs.view.DeltaToNextBand = &delta
+	}
+	return cs
+}
+
+// bandTarget is the boundary of b nearest to v, or v itself when it is
+// already inside. Exclusive bounds are returned as is; the value has to move
+// just past them.
+func bandTarget(b models.ScoreBand, v float64) float64 {
+	if b.AtLeast != nil && v < *b.AtLeast {
+		return *b.AtLeast
+	}
+	if b.Above != nil && v <= *b.Above {
+		return *b.Above
+	}
+	if b.Below != nil && v >= *b.Below {
+		return *b.Below
+	}
+	if b.AtMost != nil && v > *b.AtMost {
+		return *b.AtMost
+	}
+	return v
+}
+
+// roundAway rounds to two decimals away from zero, so a delta is never
+// understated.
+func roundAway(v float64) float64 {
+	if v < 0 {
+		return -math.Ceil(-v*100) / 100
+	}
+	return math.Ceil(v*100) / 100
+}
+
+// scoreActions turns each component's next band into the change in the
+// agency's numbers that would reach it.
+func scoreActions(in *metricsInputs, components []componentScore) []models.ScoreActionView {
+	actions := []models.ScoreActionView{}
+	burn := in.burn.monthly()
+	total := in.totalRetainer()
+
+	for _, c := range components {
+		if c.target == nil || *c.target == *c.value {
+			continue
+		}
+		target := *c.target
+		action := models.ScoreActionView{
+			Component: c.view.Component,
+			PointGain: c.view.NextBand.Points - c.view.Points,
+		}
+
+		switch c.view.Component {
+		case "retainer_safety":
+			if target < *c.value {
+				continue
+			}
+			action.Amount = target*burn - total
+			action.Action = fmt.Sprintf("Add %.2f in monthly retainers", roundAway(action.Amount))
+		case "runway":
+			if target < *c.value {
+				continue
+			}
+			action.Amount = target*burn - in.position.total
+			action.Action = fmt.Sprintf("Raise cash on hand by %.2f", roundAway(action.Amount))
+		case "client_concentration":
+			if target > *c.value || target <= 0 {
+				continue
+			}
+			action.Amount = in.maxRetainer()*100/target - total
+			action.Action = fmt.Sprintf("Add %.2f in monthly retainers from clients other than the largest", roundAway(action.Amount))
+		case "profitability":
+			if target < *c.value {
+				continue
+			}
+			action.Amount = in.costs - in.revenue*(1-target/100)
+			action.Action = fmt.Sprintf("Cut costs over the lookback window by %.2f", roundAway(action.Amount))
+		case "capacity_pressure":
+			hours := (target - *c.value) / 100 * in.capacityHours
+			action.Amount = math.Abs(hours)
+			if hours > 0 {
+				action.Action = fmt.Sprintf("Log %.1f more hours over the lookback window", action.Amount)
+			} else {
+				action.Action = fmt.Sprintf("Free up %.1f hours over the lookback window", action.Amount)
+			}
+		default:
+			continue
+		}
+		action.Amount = roundAway(action.Amount)
+		actions = append(actions, action)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].PointGain > actions[j].PointGain
+	})
+	return actions
+}
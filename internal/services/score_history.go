@@ -0,0 +1,231 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+var ErrNoScoreSnapshot = errors.New("no reality score snapshot")
+
+// defaultMoversDays is how far back the movers comparison looks by default.
+const defaultMoversDays = 90
+
+type ScoreHistoryService interface {
+	GetScoreHistory(agencyID string, from string, to string) (*models.ScoreHistoryView, error)
+	GetScoreMovers(agencyID string, from string, to string) (*models.ScoreMoversView, error)
+	SnapshotScores() error
+}
+
+type scoreHistoryService struct {
+	agencyRepo   repository.AgencyRepository
+	snapshotRepo repository.ScoreSnapshotRepository
+	settingsRepo repository.SettingsRepository
+	scoringRepo  repository.ScoringProfileRepository
+	metrics      metricsLoader
+	clock        clock.Clock
+}
+
+func NewScoreHistoryService(
+	agencyRepo repository.AgencyRepository,
+	snapshotRepo repository.ScoreSnapshotRepository,
+	bankRepo repository.BankAccountRepository,
+	cashRepo repository.CashSnapshotRepository,
+	financeRepo repository.FinanceRepository,
+	recurringRepo repository.RecurringCostRepository,
+	retainerRepo repository.RetainerRepository,
+	timeRepo repository.TimeEntryRepository,
+	settingsRepo repository.SettingsRepository,
+	scoringRepo repository.ScoringProfileRepository,
+	clk clock.Clock,
+) ScoreHistoryService {
+	return &scoreHistoryService{
+		agencyRepo:   agencyRepo,
+		snapshotRepo: snapshotRepo,
+		settingsRepo: settingsRepo,
+		scoringRepo:  scoringRepo,
+		metrics: metricsLoader{
+			bankRepo:     bankRepo,
+			cashRepo:     cashRepo,
+			financeRepo:  financeRepo,
+			retainerRepo: retainerRepo,
+			timeRepo:     timeRepo,
+			burn:         burnCalculator{financeRepo: financeRepo, recurringRepo: recurringRepo},
+		},
+		clock: clk,
+	}
+}
+
+// SnapshotScores stores every agency's current score under its local date.
+// It runs from the scheduler; later runs on the same day overwrite earlier
+// ones, so each day keeps the last score computed.
+func (s *scoreHistoryService) SnapshotScores() error {
+	ids, err := s.agencyRepo.ListIDs()
+	if err != nil {
+		return err
+	}
+	var failed []error
+	for _, id := range ids {
+		if err := s.snapshot(id); err != nil {
+			failed = append(failed, fmt.Errorf("agency %s: %w", id, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+func (s *scoreHistoryService) snapshot(agencyID string) error {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	in, err := s.metrics.load(ac, agencyID)
+	if err != nil {
+		return err
+	}
+	sc, err := loadScoring(s.scoringRepo, agencyID)
+	if err != nil {
+		return err
+	}
+	score := computeRealityScore(in, sc)
+
+	inputs, err := json.Marshal(in.view())
+	if err != nil {
+		return err
+	}
+	return s.snapshotRepo.Upsert(repository.ScoreSnapshotEntity{
+		AgencyID:            agencyID,
+		Date:                ac.today(),
+		Score:               score.Score,
+		Status:              score.Status,
+		PrimaryRisk:         score.PrimaryRisk,
+		ProfileVersion:      score.ProfileVersion,
+		RetainerSafety:      score.Breakdown.RetainerSafety,
+		Runway:              score.Breakdown.Runway,
+		ClientConcentration: score.Breakdown.ClientConcentration,
+		Profitability:       score.Breakdown.Profitability,
+		CapacityPressure:    score.Breakdown.CapacityPressure,
+		Inputs:              inputs,
+	})
+}
+
+// GetScoreHistory lists stored snapshots between from and to, defaulting to
+// the lookback window ending today.
+func (s *scoreHistoryService) GetScoreHistory(agencyID string, from string, to string) (*models.ScoreHistoryView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err = ac.dateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	if end.Sub(start) > maxHistoryDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: range is limited to %d days", ErrInvalidDateRange, maxHistoryDays)
+	}
+
+	snapshots, err := s.snapshotRepo.ListBetween(agencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	view := &models.ScoreHistoryView{
+		From:      from,
+		To:        to,
+		Snapshots: make([]models.ScoreSnapshotView, len(snapshots)),
+	}
+	for i, snap := range snapshots {
+		if view.Snapshots[i], err = toScoreSnapshotView(snap); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// GetScoreMovers compares the latest snapshot on or before from with the
+// latest on or before to, component by component. to defaults to today and
+// from to 90 days earlier.
+func (s *scoreHistoryService) GetScoreMovers(agencyID string, from string, to string) (*models.ScoreMoversView, error) {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if from == "" {
+		from = ac.now.AddDate(0, 0, -defaultMoversDays).Format("2006-01-02")
+	}
+	if from, to, err = ac.dateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	before, err := s.snapshotRepo.GetOnOrBefore(agencyID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.snapshotRepo.GetOnOrBefore(agencyID, to)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("%w: none recorded on or before %s", ErrNoScoreSnapshot, from)
+	}
+
+	view := &models.ScoreMoversView{
+		ScoreChange:    after.Score - before.Score,
+		ProfileChanged: after.ProfileVersion != before.ProfileVersion,
+		Components: []models.ScoreComponentMoveView{
+			{Component: "retainer_safety", From: before.RetainerSafety, To: after.RetainerSafety},
+			{Component: "runway", From: before.Runway, To: after.Runway},
+			{Component: "client_concentration", From: before.ClientConcentration, To: after.ClientConcentration},
+			{Component: "profitability", From: before.Profitability, To: after.Profitability},
+			{Component: "capacity_pressure", From: before.CapacityPressure, To: after.CapacityPressure},
+		},
+	}
+	if view.From, err = toScoreSnapshotView(*before); err != nil {
+		return nil, err
+	}
+	if view.To, err = toScoreSnapshotView(*after); err != nil {
+		return nil, err
+	}
+	for i := range view.Components {
+		m := &view.Components[i]
+		m.Change = m.To - m.From
+	}
+	sort.SliceStable(view.Components, func(i, j int) bool {
+		return abs(view.Components[i].Change) > abs(view.Components[j].Change)
+	})
+	return view, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func toScoreSnapshotView(s repository.ScoreSnapshotEntity) (models.ScoreSnapshotView, error) {
+	view := models.ScoreSnapshotView{
+		Date:  s.Date,
+		Score: s.Score,
+		Breakdown: models.ScoreBreakdownView{
+			RetainerSafety:      s.RetainerSafety,
+			Runway:              s.Runway,
+			ClientConcentration: s.ClientConcentration,
+			Profitability:       s.Profitability,
+			CapacityPressure:    s.CapacityPressure,
+		},
+		Status:         s.Status,
+		PrimaryRisk:    s.PrimaryRisk,
+		ProfileVersion: s.ProfileVersion,
+	}
+	if err := json.Unmarshal(s.Inputs, &view.Inputs); err != nil {
+		return view, err
+	}
+	return view, nil
+}
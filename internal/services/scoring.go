@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+var ErrInvalidScoringProfile = errors.New("invalid scoring profile")
+
+// DefaultScoringVersion tags scores computed with the built-in profile.
+// Bump it whenever defaultScoringProfile changes.
+const DefaultScoringVersion = "default-v1"
+
+// StatusInsufficientData replaces the score's status when inputs it depends
+// on haven't been recorded yet.
+const StatusInsufficientData = "insufficient_data"
+
+// maxScoreBands bounds the number of bands per component.
+const maxScoreBands = 10
+
+func bound(v float64) *float64 { return &v }
+
+// defaultScoringProfile is the original fixed scoring: components worth
+// 25/20/20/20/15 points and Healthy/Watch/At Risk cutoffs of 80/60/40.
+func defaultScoringProfile() models.ScoringProfile {
+	return models.ScoringProfile{
+		RetainerSafety: models.ScoreComponentProfile{
+			Weight: 25,
+			Bands: []models.ScoreBand{
+				{Points: 25, AtLeast: bound(1.5)},
+				{Points: 20, AtLeast: bound(1.2)},
+				{Points: 15, AtLeast: bound(1.0)},
+				{Points: 10, AtLeast: bound(0.8)},
+			},
+		},
+		Runway: models.ScoreComponentProfile{
+			Weight: 20,
+			Bands: []models.ScoreBand{
+				{Points: 20, AtLeast: bound(6)},
+				{Points: 15, AtLeast: bound(4)},
+				{Points: 8, AtLeast: bound(2)},
+				{Points: 4, AtLeast: bound(1)},
+			},
+		},
+		ClientConcentration: models.ScoreComponentProfile{
+			Weight: 20,
+			Bands: []models.ScoreBand{
+				{Points: 20, Below: bound(30)},
+				{Points: 15, Below: bound(40)},
+				{Points: 8, Below: bound(50)},
+				{Points: 4, Below: bound(60)},
+			},
+		},
+		Profitability: models.ScoreComponentProfile{
+			Weight: 20,
+			Bands: []models.ScoreBand{
+				{Points: 20, AtLeast: bound(20)},
+				{Points: 15, AtLeast: bound(10)},
+				{Points: 8, AtLeast: bound(0)},
+				{Points: 4, AtLeast: bound(-10)},
+			},
+		},
+		CapacityPressure: models.ScoreComponentProfile{
+			Weight: 15,
+			Bands: []models.ScoreBand{
+				{Points: 15, AtLeast: bound(60), AtMost: bound(85)},
+				{Points: 10, AtLeast: bound(50), Below: bound(60)},
+				{Points: 6, AtLeast: bound(40), Below: bound(50)},
+				{Points: 6, Above: bound(85), AtMost: bound(100)},
+			},
+		},
+		StatusCutoffs: models.StatusCutoffs{Healthy: 80, Watch: 60, AtRisk: 40},
+	}
+}
+
+// scoring is the profile a score is computed with and its version tag.
+type scoring struct {
+	profile models.ScoringProfile
+	version string
+}
+
+func loadScoring(scoringRepo repository.ScoringProfileRepository, agencyID string) (*scoring, error) {
+	entity, err := scoringRepo.Get(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.Profile == nil {
+		return &scoring{profile: defaultScoringProfile(), version: DefaultScoringVersion}, nil
+	}
+	sc := scoring{version: fmt.Sprintf("custom-v%d", entity.Version)}
+	if err := json.Unmarshal(entity.Profile, &sc.profile); err != nil {
+		return nil, fmt.Errorf("scoring profile for agency %s: %w", agencyID, err)
+	}
+	return &sc, nil
+}
+
+// bandContains reports whether v meets every bound set on b. A component
+// scores the first band that contains its value.
+func bandContains(b models.ScoreBand, v float64) bool {
+	return (b.AtLeast == nil || v >= *b.AtLeast) &&
+		(b.Above == nil || v > *b.Above) &&
+		(b.Below == nil || v < *b.Below) &&
+		(b.AtMost == nil || v <= *b.AtMost)
+}
+
+func (sc *scoring) status(score int) string {
+	cutoffs := sc.profile.StatusCutoffs
+	if score >= cutoffs.Healthy {
+		return "Healthy"
+	} else if score >= cutoffs.Watch {
+		return "Watch"
+	} else if score >= cutoffs.AtRisk {
+		return "At Risk"
+	}
+	return "Danger"
+}
+
+// validateScoringProfile checks that weights add up to 100, every band fits
+// within its component's weight and has a consistent range, and the status
+// cutoffs are strictly descending within 1-100.
+func validateScoringProfile(p models.ScoringProfile) error {
+	components := []struct {
+		name string
+		c    models.ScoreComponentProfile
+	}{
+		{"retainer_safety", p.RetainerSafety},
+		{"runway", p.Runway},
+		{"client_concentration", p.ClientConcentration},
+		{"profitability", p.Profitability},
+		{"capacity_pressure", p.CapacityPressure},
+	}
+
+	total := 0
+	for _, comp := range components {
+		if comp.c.Weight < 0 {
+			return fmt.Errorf("%w: %s: weight must not be negative", ErrInvalidScoringProfile, comp.name)
+		}
+		total += comp.c.Weight
+		if len(comp.c.Bands) > maxScoreBands {
+			return fmt.Errorf("%w: %s: at most %d bands", ErrInvalidScoringProfile, comp.name, maxScoreBands)
+		}
+		for i, b := range comp.c.Bands {
+			if err := validateScoreBand(b, comp.c.Weight); err != nil {
+				return fmt.Errorf("%w: %s band %d: %s", ErrInvalidScoringProfile, comp.name, i+1, err)
+			}
+		}
+	}
+	if total != 100 {
+		return fmt.Errorf("%w: weights must add up to 100, got %d", ErrInvalidScoringProfile, total)
+	}
+
+	c := p.StatusCutoffs
+	if !(c.Healthy <= 100 && c.Healthy > c.Watch && c.Watch > c.AtRisk && c.AtRisk > 0) {
+		return fmt.Errorf("%w: status cutoffs must satisfy 100 >= healthy > watch > at_risk > 0", ErrInvalidScoringProfile)
+	}
+	return nil
+}
+
+func validateScoreBand(b models.ScoreBand, weight int) error {
+	if b.Points < 0 || b.Points > weight {
+		return fmt.Errorf("points must be 0-%d", weight)
+	}
+	if b.AtLeast != nil && b.Above != nil {
+		return errors.New("set at most one of at_least and above")
+	}
+	if b.Below != nil && b.AtMost != nil {
+		return errors.New("set at most one of below and at_most")
+	}
+	lower, upper := b.AtLeast, b.Below
+	if lower == nil {
+		lower = b.Above
+	}
+	if upper == nil {
+		upper = b.AtMost
+	}
+	if lower == nil && upper == nil {
+		return errors.New("set at least one bound")
+	}
+	for _, v := range []*float64{lower, upper} {
+		if v != nil && (math.IsNaN(*v) || math.IsInf(*v, 0)) {
+			return errors.New("bounds must be finite")
+		}
+	}
+	if lower != nil && upper != nil && *lower > *upper {
+		return errors.New("lower bound is above upper bound")
+	}
+	return nil
+}
+
+type ScoringService interface {
+	GetScoringProfile(agencyID string) (*models.ScoringProfileView, error)
+	UpdateScoringProfile(agencyID string, userID string, profile models.ScoringProfile) (*models.ScoringProfileView, error)
+	ResetScoringProfile(agencyID string, userID string) (*models.ScoringProfileView, error)
+}
+
+type scoringService struct {
+	scoringRepo repository.ScoringProfileRepository
+}
+
+func NewScoringService(scoringRepo repository.ScoringProfileRepository) ScoringService {
+	return &scoringService{scoringRepo: scoringRepo}
+}
+
+func (s *scoringService) GetScoringProfile(agencyID string) (*models.ScoringProfileView, error) {
+	entity, err := s.scoringRepo.Get(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	return toScoringProfileView(entity)
+}
+
+func (s *scoringService) UpdateScoringProfile(agencyID string, userID string, profile models.ScoringProfile) (*models.ScoringProfileView, error) {
+	if err := validateScoringProfile(profile); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	entity, err := s.scoringRepo.Save(agencyID, data, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toScoringProfileView(entity)
+}
+
+// ResetScoringProfile returns the agency to the built-in profile.
+func (s *scoringService) ResetScoringProfile(agencyID string, userID string) (*models.ScoringProfileView, error) {
+	entity, err := s.scoringRepo.Save(agencyID, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toScoringProfileView(entity)
+}
+
+func toScoringProfileView(entity *repository.ScoringProfileEntity) (*models.ScoringProfileView, error) {
+	if entity == nil || entity.Profile == nil {
+		view := &models.ScoringProfileView{
+			Version:   DefaultScoringVersion,
+			IsDefault: true,
+			Profile:   defaultScoringProfile(),
+		}
+		if entity != nil {
+			view.UpdatedAt = &entity.UpdatedAt
+		}
+		return view, nil
+	}
+	view := &models.ScoringProfileView{
+		Version:   fmt.Sprintf("custom-v%d", entity.Version),
+		UpdatedAt: &entity.UpdatedAt,
+	}
+	if err := json.Unmarshal(entity.Profile, &view.Profile); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+func defaultScoring() *scoring {
+	return &scoring{profile: defaultScoringProfile(), version: DefaultScoringVersion}
+}
+
+func TestDefaultProfileBandEdges(t *testing.T) {
+	p := defaultScoringProfile()
+	tests := []struct {
+		component string
+		profile   models.ScoreComponentProfile
+		value     float64
+		want      int
+	}{
+		{"retainer_safety", p.RetainerSafety, 1.5, 25},
+		{"retainer_safety", p.RetainerSafety, 1.4999, 20},
+		{"retainer_safety", p.RetainerSafety, 1.2, 20},
+		{"retainer_safety", p.RetainerSafety, 1.1999, 15},
+		{"retainer_safety", p.RetainerSafety, 1.0, 15},
+		{"retainer_safety", p.RetainerSafety, 0.9999, 10},
+		{"retainer_safety", p.RetainerSafety, 0.8, 10},
+		{"retainer_safety", p.RetainerSafety, 0.7999, 0},
+		{"retainer_safety", p.RetainerSafety, 0, 0},
+
+		{"runway", p.Runway, 6, 20},
+		{"runway", p.Runway, 5.99, 15},
+		{"runway", p.Runway, 4, 15},
+		{"runway", p.Runway, 3.99, 8},
+		{"runway", p.Runway, 2, 8},
+		{"runway", p.Runway, 1.99, 4},
+		{"runway", p.Runway, 1, 4},
+		{"runway", p.Runway, 0.99, 0},
+		{"runway", p.Runway, -1, 0},
+
+		{"client_concentration", p.ClientConcentration, 0, 20},
+		{"client_concentration", p.ClientConcentration, 29.99, 20},
+		{"client_concentration", p.ClientConcentration, 30, 15},
+		{"client_concentration", p.ClientConcentration, 39.99, 15},
+		{"client_concentration", p.ClientConcentration, 40, 8},
+		{"client_concentration", p.ClientConcentration, 49.99, 8},
+		{"client_concentration", p.ClientConcentration, 50, 4},
+		{"client_concentration", p.ClientConcentration, 59.99, 4},
+		{"client_concentration", p.ClientConcentration, 60, 0},
+		{"client_concentration", p.ClientConcentration, 100, 0},
+
+		{"profitability", p.Profitability, 20, 20},
+		{"profitability", p.Profitability, 19.99, 15},
+		{"profitability", p.Profitability, 10, 15},
+		{"profitability", p.Profitability, 9.99, 8},
+		{"profitability", p.Profitability, 0, 8},
+		{"profitability", p.Profitability, -0.01, 4},
+		{"profitability", p.Profitability, -10, 4},
+		{"profitability", p.Profitability, -10.01, 0},
+
+		{"capacity_pressure", p.CapacityPressure, 39.99, 0},
+		{"capacity_pressure", p.CapacityPressure, 40, 6},
+		{"capacity_pressure", p.CapacityPressure, 49.99, 6},
+		{"capacity_pressure", p.CapacityPressure, 50, 10},
+		{"capacity_pressure", p.CapacityPressure, 59.99, 10},
+		{"capacity_pressure", p.CapacityPressure, 60, 15},
+		{"capacity_pressure", p.CapacityPressure, 85, 15},
+		{"capacity_pressure", p.CapacityPressure, 85.01, 6},
+		{"capacity_pressure", p.CapacityPressure, 100, 6},
+		{"capacity_pressure", p.CapacityPressure, 100.01, 0},
+	}
+
+	for _, tt := range tests {
+		v := tt.value
+		got := scoreComponent(tt.component, "", tt.profile, &v).view.Points
+		if got != tt.want {
+			t.Errorf("%s at %v: got %d points, want %d", tt.component, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultProfileStatusCutoffs(t *testing.T) {
+	sc := defaultScoring()
+	tests := []struct {
+		score int
+		want  string
+	}{
+		{100, "Healthy"},
+		{80, "Healthy"},
+		{79, "Watch"},
+		{60, "Watch"},
+		{59, "At Risk"},
+		{40, "At Risk"},
+		{39, "Danger"},
+		{0, "Danger"},
+	}
+
+	for _, tt := range tests {
+		if got := sc.status(tt.score); got != tt.want {
+			t.Errorf("status(%d) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+// retainers returns one retainer per amount.
+func retainers(amounts ...float64) []repository.RetainerEntity {
+	var rs []repository.RetainerEntity
+	for _, a := range amounts {
+		rs = append(rs, repository.RetainerEntity{MonthlyAmount: a})
+	}
+	return rs
+}
+
+func TestComputeRealityScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          metricsInputs
+		wantScore   int
+		wantStatus  string
+		wantRisk    string
+		wantMissing []string
+	}{
+		{
+			name: "full marks",
+			in: metricsInputs{
+				position:      &cashPosition{total: 60000},
+				burn:          burnFigures{recurring: 10000},
+				retainers:     retainers(4000, 4000, 4000, 4000),
+				revenue:       20000,
+				costs:         10000,
+				usedHours:     70,
+				capacityHours: 100,
+			},
+			wantScore:   100,
+			wantStatus:  "Healthy",
+			wantRisk:    "Healthy",
+			wantMissing: []string{},
+		},
+		{
+			name: "no named risk blames the weakest component",
+			in: metricsInputs{
+				position:      &cashPosition{total: 60000},
+				burn:          burnFigures{recurring: 10000},
+				retainers:     retainers(4000, 4000, 4000, 4000),
+				revenue:       20000,
+				costs:         19000,
+				usedHours:     45,
+				capacityHours: 100,
+			},
+			wantScore:   79,
+			wantStatus:  "Watch",
+			wantRisk:    "Low Profitability",
+			wantMissing: []string{},
+		},
+		{
+			name: "fixed costs above retainers",
+			in: metricsInputs{
+				position:      &cashPosition{total: 5000},
+				burn:          burnFigures{recurring: 10000},
+				retainers:     retainers(3000, 3000),
+				revenue:       6000,
+				costs:         10000,
+				usedHours:     70,
+				capacityHours: 100,
+			},
+			wantScore:   19,
+			wantStatus:  "Danger",
+			wantRisk:    "High Fixed Costs",
+			wantMissing: []string{},
+		},
+		{
+			// Coverage and runway divide by fixed costs; they must score zero
+			// rather than Inf/NaN, and the fixed-cost risks must not fire
+			name: "zero fixed costs with variable spend",
+			in: metricsInputs{
+				position:      &cashPosition{total: 10000},
+				retainers:     retainers(5000),
+				revenue:       10000,
+				costs:         500,
+				usedHours:     70,
+				capacityHours: 100,
+			},
+			wantScore:   35,
+			wantStatus:  "Danger",
+			wantRisk:    "Client Concentration",
+			wantMissing: []string{},
+		},
+		{
+			name: "zero capacity scores utilization as zero",
+			in: metricsInputs{
+				position:  &cashPosition{total: 60000},
+				burn:      burnFigures{recurring: 10000},
+				retainers: retainers(4000, 4000, 4000, 4000),
+				revenue:   20000,
+				costs:     10000,
+				usedHours: 70,
+			},
+			wantScore:   85,
+			wantStatus:  "Healthy",
+			wantRisk:    "Healthy",
+			wantMissing: []string{},
+		},
+		{
+			name: "zero fixed costs and no spend",
+			in: metricsInputs{
+				position:      &cashPosition{total: 10000},
+				retainers:     retainers(5000),
+				revenue:       10000,
+				usedHours:     70,
+				capacityHours: 100,
+			},
+			wantScore:   35,
+			wantStatus:  "insufficient_data",
+			wantRisk:    "Insufficient Data",
+			wantMissing: []string{"costs"},
+		},
+		{
+			name: "no cash snapshot",
+			in: metricsInputs{
+				burn:          burnFigures{recurring: 10000},
+				retainers:     retainers(4000, 4000, 4000, 4000),
+				revenue:       20000,
+				costs:         10000,
+				usedHours:     70,
+				capacityHours: 100,
+			},
+			wantScore:   80,
+			wantStatus:  "insufficient_data",
+			wantRisk:    "Insufficient Data",
+			wantMissing: []string{"cash_snapshot"},
+		},
+		{
+			name:        "nothing recorded",
+			in:          metricsInputs{},
+			wantScore:   0,
+			wantStatus:  "insufficient_data",
+			wantRisk:    "Insufficient Data",
+			wantMissing: []string{"cash_snapshot", "costs", "time_entries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeRealityScore(&tt.in, defaultScoring())
+			if got.Score != tt.wantScore {
+				t.Errorf("score = %d, want %d", got.Score, tt.wantScore)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if got.PrimaryRisk != tt.wantRisk {
+				t.Errorf("primary risk = %q, want %q", got.PrimaryRisk, tt.wantRisk)
+			}
+			if !reflect.DeepEqual(got.MissingInputs, tt.wantMissing) {
+				t.Errorf("missing inputs = %v, want %v", got.MissingInputs, tt.wantMissing)
+			}
+			for _, c := range got.Components {
+				if c.Value == nil && c.Points != 0 {
+					t.Errorf("%s scored %d points without a value", c.Component, c.Points)
+				}
+			}
+		})
+	}
+}
+
+func TestWeakestComponentRisk(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []models.ScoreComponentView
+		want       string
+	}{
+		{
+			name: "largest shortfall wins",
+			components: []models.ScoreComponentView{
+				{Component: "retainer_safety", Points: 20, PointsAvailable: 25},
+				{Component: "capacity_pressure", Points: 0, PointsAvailable: 15},
+				{Component: "runway", Points: 8, PointsAvailable: 20},
+			},
+			want: "Capacity Pressure",
+		},
+		{
+			name: "ties go to the first component",
+			components: []models.ScoreComponentView{
+				{Component: "runway", Points: 12, PointsAvailable: 20},
+				{Component: "profitability", Points: 12, PointsAvailable: 20},
+			},
+			want: "Low Runway",
+		},
+		{
+			name: "full marks",
+			components: []models.ScoreComponentView{
+				{Component: "runway", Points: 20, PointsAvailable: 20},
+			},
+			want: "Healthy",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := weakestComponentRisk(tt.components); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
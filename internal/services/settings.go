@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+var (
+	ErrInvalidSettings  = errors.New("invalid settings")
+	ErrInvalidEntryDate = errors.New("invalid entry date")
+	ErrInvalidDateRange = errors.New("invalid date range")
+)
+
+// SettingsUpdate is a partial update; nil fields are left unchanged.
+type SettingsUpdate struct {
+	Timezone             *string
+	FiscalYearStartMonth *int
+	LookbackDays         *int
+	MonthlyCapacityHours *float64
+	FutureToleranceDays  *int
+	BurnAverageMonths    *int
+	CashSafetyFloor      *float64
+}
+
+type SettingsService interface {
+	GetSettings(agencyID string) (*models.AgencySettingsView, error)
+	UpdateSettings(agencyID string, update SettingsUpdate) (*models.AgencySettingsView, error)
+}
+
+type settingsService struct {
+	settingsRepo repository.SettingsRepository
+}
+
+func NewSettingsService(settingsRepo repository.SettingsRepository) SettingsService {
+	return &settingsService{settingsRepo: settingsRepo}
+}
+
+func (s *settingsService) GetSettings(agencyID string) (*models.AgencySettingsView, error) {
+	settings, err := s.settingsRepo.Get(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	return toSettingsView(*settings), nil
+}
+
+func (s *settingsService) UpdateSettings(agencyID string, update SettingsUpdate) (*models.AgencySettingsView, error) {
+	settings, err := s.settingsRepo.Get(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Timezone != nil {
+		// LoadLocation maps "" and "Local" to the server's own zone, which is
+		// exactly what per-agency timezones replace
+		if _, err := time.LoadLocation(*update.Timezone); err != nil || *update.Timezone == "" || *update.Timezone == "Local" {
+			return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSettings, *update.Timezone)
+		}
+		settings.Timezone = *update.Timezone
+	}
+	if update.FiscalYearStartMonth != nil {
+		if *update.FiscalYearStartMonth < 1 || *update.FiscalYearStartMonth > 12 {
+			return nil, fmt.Errorf("%w: fiscal_year_start_month must be 1-12", ErrInvalidSettings)
+		}
+		settings.FiscalYearStartMonth = *update.FiscalYearStartMonth
+	}
+	if update.LookbackDays != nil {
+		if *update.LookbackDays < 1 || *update.LookbackDays > 366 {
+			return nil, fmt.Errorf("%w: lookback_days must be 1-366", ErrInvalidSettings)
+		}
+		settings.LookbackDays = *update.LookbackDays
+	}
+	if update.MonthlyCapacityHours != nil {
+		if *update.MonthlyCapacityHours <= 0 {
+			return nil, fmt.Errorf("%w: monthly_capacity_hours must be positive", ErrInvalidSettings)
+		}
+		settings.MonthlyCapacityHours = *update.MonthlyCapacityHours
+	}
+	if update.FutureToleranceDays != nil {
+		if *update.FutureToleranceDays < 0 || *update.FutureToleranceDays > 31 {
+			return nil, fmt.Errorf("%w: future_entry_tolerance_days must be 0-31", ErrInvalidSettings)
+		}
+		settings.FutureToleranceDays = *update.FutureToleranceDays
+	}
+	if update.BurnAverageMonths != nil {
+		switch *update.BurnAverageMonths {
+		case 1, 3, 6:
+			settings.BurnAverageMonths = *update.BurnAverageMonths
+		default:
+			return nil, fmt.Errorf("%w: burn_average_months must be 1, 3 or 6", ErrInvalidSettings)
+		}
+	}
+	if update.CashSafetyFloor != nil {
+		if *update.CashSafetyFloor < 0 {
+			return nil, fmt.Errorf("%w: cash_safety_floor must not be negative", ErrInvalidSettings)
+		}
+		settings.CashSafetyFloor = *update.CashSafetyFloor
+	}
+
+	if err := s.settingsRepo.Save(*settings); err != nil {
+		return nil, err
+	}
+	return toSettingsView(*settings), nil
+}
+
+func toSettingsView(s repository.AgencySettingsEntity) *models.AgencySettingsView {
+	return &models.AgencySettingsView{
+		Timezone:             s.Timezone,
+		FiscalYearStartMonth: s.FiscalYearStartMonth,
+		LookbackDays:         s.LookbackDays,
+		MonthlyCapacityHours: s.MonthlyCapacityHours,
+		FutureToleranceDays:  s.FutureToleranceDays,
+		BurnAverageMonths:    s.BurnAverageMonths,
+		CashSafetyFloor:      s.CashSafetyFloor,
+	}
+}
+
+// daysPerMonth is the average length of a Gregorian month.
+const daysPerMonth = 30.44
+
+// agencyContext is an agency's settings plus "now" in its timezone. Services
+// derive every date and reporting window from it.
+type agencyContext struct {
+	settings repository.AgencySettingsEntity
+	now      time.Time
+}
+
+func loadAgencyContext(settingsRepo repository.SettingsRepository, clk clock.Clock, agencyID string) (*agencyContext, error) {
+	settings, err := settingsRepo.Get(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &agencyContext{settings: *settings, now: clk.Now().In(loc)}, nil
+}
+
+// today is the agency's local calendar date.
+func (c *agencyContext) today() string {
+	return c.now.Format("2006-01-02")
+}
+
+// windowStart is the first date of the default lookback window. AddDate works
+// on the local calendar, so DST transitions don't shift the boundary.
+func (c *agencyContext) windowStart() string {
+	return c.now.AddDate(0, 0, -c.settings.LookbackDays).Format("2006-01-02")
+}
+
+// windowMonths is the lookback window's length in months, for scaling monthly
+// figures such as capacity hours to it.
+func (c *agencyContext) windowMonths() float64 {
+	return float64(c.settings.LookbackDays) / daysPerMonth
+}
+
+// entryDate resolves the date a ledger entry is booked on: today when none is
+// requested, otherwise a date between the agency's creation and the configured
+// number of days ahead.
+func (c *agencyContext) entryDate(requested string, agencyCreatedAt time.Time) (string, error) {
+	if requested == "" {
+		return c.today(), nil
+	}
+
+	d, err := time.ParseInLocation("2006-01-02", requested, c.now.Location())
+	if err != nil {
+		return "", fmt.Errorf("%w: expected YYYY-MM-DD", ErrInvalidEntryDate)
+	}
+	date := d.Format("2006-01-02")
+
+	latest := c.now.AddDate(0, 0, c.settings.FutureToleranceDays).Format("2006-01-02")
+	if date > latest {
+		return "", fmt.Errorf("%w: %s is too far in the future", ErrInvalidEntryDate, date)
+	}
+
+	earliest := agencyCreatedAt.In(c.now.Location()).Format("2006-01-02")
+	if date < earliest {
+		return "", fmt.Errorf("%w: %s is before the agency was created", ErrInvalidEntryDate, date)
+	}
+
+	return date, nil
+}
+
+// dateRange validates optional from/to query dates, defaulting to the
+// lookback window ending today.
+func (c *agencyContext) dateRange(from string, to string) (string, string, error) {
+	if from == "" {
+		from = c.windowStart()
+	}
+	if to == "" {
+		to = c.today()
+	}
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return "", "", fmt.Errorf("%w: expected YYYY-MM-DD", ErrInvalidDateRange)
+		}
+	}
+	if from > to {
+		return "", "", fmt.Errorf("%w: from is after to", ErrInvalidDateRange)
+	}
+	return from, to, nil
+}
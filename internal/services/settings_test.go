@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+// Australia/Sydney moves from AEDT (+11) to AEST (+10) at 03:00 on
+// 2026-04-05 and back to AEDT at 02:00 on 2026-10-04.
+var sydneyDSTCases = []struct {
+	name        string
+	now         string
+	today       string
+	windowStart string
+}{
+	{"last second of the day before DST ends", "2026-04-04T12:59:59Z", "2026-04-04", "2026-03-05"},
+	{"midnight on the day DST ends", "2026-04-04T13:00:00Z", "2026-04-05", "2026-03-06"},
+	{"first hour after DST ends", "2026-04-04T16:30:00Z", "2026-04-05", "2026-03-06"},
+	{"last second of the day DST ends", "2026-04-05T13:59:59Z", "2026-04-05", "2026-03-06"},
+	{"midnight after DST ends", "2026-04-05T14:00:00Z", "2026-04-06", "2026-03-07"},
+	{"last second of the day before DST starts", "2026-10-03T13:59:59Z", "2026-10-03", "2026-09-03"},
+	{"midnight on the day DST starts", "2026-10-03T14:00:00Z", "2026-10-04", "2026-09-04"},
+	{"first hour after DST starts", "2026-10-03T16:00:00Z", "2026-10-04", "2026-09-04"},
+	{"last second of the day DST starts", "2026-10-04T12:59:59Z", "2026-10-04", "2026-09-04"},
+	{"midnight after DST starts", "2026-10-04T13:00:00Z", "2026-10-05", "2026-09-05"},
+}
+
+func sydneySettings() *fakeSettingsRepo {
+	s := repository.DefaultAgencySettings("")
+	s.Timezone = "Australia/Sydney"
+	return &fakeSettingsRepo{settings: s}
+}
+
+func fixedAt(t *testing.T, rfc3339 string) clock.Fixed {
+	now, err := time.Parse(time.RFC3339, rfc3339)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clock.Fixed{T: now}
+}
+
+func TestLedgerDatesAcrossSydneyDST(t *testing.T) {
+	for _, tt := range sydneyDSTCases {
+		t.Run(tt.name, func(t *testing.T) {
+			financeRepo := &fakeFinanceRepo{}
+			agencyRepo := &fakeAgencyRepo{createdAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+			svc := NewFinanceService(agencyRepo, nil, nil, financeRepo, nil, nil, nil, sydneySettings(), nil, nil, nil, fixedAt(t, tt.now))
+
+			if _, err := svc.AddRevenue("agency-1", "", 100, "client"); err != nil {
+				t.Fatalf("AddRevenue: %v", err)
+			}
+			if _, err := svc.AddCost("agency-1", "", 50, "fixed", "rent", "overhead"); err != nil {
+				t.Fatalf("AddCost: %v", err)
+			}
+			if got := financeRepo.revenues[0].Date; got != tt.today {
+				t.Errorf("revenue date = %s, want %s", got, tt.today)
+			}
+			if got := financeRepo.costs[0].Date; got != tt.today {
+				t.Errorf("cost date = %s, want %s", got, tt.today)
+			}
+
+			summary, err := svc.GetDailySummary("agency-1")
+			if err != nil {
+				t.Fatalf("GetDailySummary: %v", err)
+			}
+			if summary.Date != tt.today {
+				t.Errorf("summary date = %s, want %s", summary.Date, tt.today)
+			}
+			for _, d := range financeRepo.sumDates {
+				if d != tt.today {
+					t.Errorf("summary summed %s, want %s", d, tt.today)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeEntryDatesAcrossSydneyDST(t *testing.T) {
+	for _, tt := range sydneyDSTCases {
+		t.Run(tt.name, func(t *testing.T) {
+			timeRepo := &fakeTimeRepo{}
+			agencyRepo := &fakeAgencyRepo{createdAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+			svc := NewUtilizationService(agencyRepo, timeRepo, sydneySettings(), nil, fixedAt(t, tt.now))
+
+			if err := svc.AddTimeEntry("agency-1", nil, "", 4); err != nil {
+				t.Fatalf("AddTimeEntry: %v", err)
+			}
+			if got := timeRepo.dates[0]; got != tt.today {
+				t.Errorf("time entry date = %s, want %s", got, tt.today)
+			}
+
+			if _, err := svc.GetUtilization("agency-1"); err != nil {
+				t.Fatalf("GetUtilization: %v", err)
+			}
+			if timeRepo.windowStart != tt.windowStart {
+				t.Errorf("window start = %s, want %s", timeRepo.windowStart, tt.windowStart)
+			}
+		})
+	}
+}
+
+func TestEntryDateToleranceAcrossSydneyDST(t *testing.T) {
+	financeRepo := &fakeFinanceRepo{}
+	agencyRepo := &fakeAgencyRepo{createdAt: time.Date(2026, 10, 4, 0, 0, 0, 0, time.UTC)}
+	// 23:30 AEDT on 2026-10-04, when UTC is still on the 4th too
+	svc := NewFinanceService(agencyRepo, nil, nil, financeRepo, nil, nil, nil, sydneySettings(), nil, nil, nil, fixedAt(t, "2026-10-04T12:30:00Z"))
+
+	if _, err := svc.AddRevenue("agency-1", "2026-10-04", 100, "client"); err != nil {
+		t.Errorf("today rejected: %v", err)
+	}
+	if _, err := svc.AddRevenue("agency-1", "2026-10-05", 100, "client"); !errors.Is(err, ErrInvalidEntryDate) {
+		t.Errorf("tomorrow: err = %v, want ErrInvalidEntryDate", err)
+	}
+	// The agency was created at 11:00 local on the 4th, not the 3rd
+	if _, err := svc.AddRevenue("agency-1", "2026-10-03", 100, "client"); !errors.Is(err, ErrInvalidEntryDate) {
+		t.Errorf("before creation: err = %v, want ErrInvalidEntryDate", err)
+	}
+}
+
+func TestUpdateSettingsRejectsServerZone(t *testing.T) {
+	svc := NewSettingsService(&fakeSettingsRepo{settings: repository.DefaultAgencySettings("")})
+	for _, tz := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		tz := tz
+		if _, err := svc.UpdateSettings("agency-1", SettingsUpdate{Timezone: &tz}); !errors.Is(err, ErrInvalidSettings) {
+			t.Errorf("timezone %q: err = %v, want ErrInvalidSettings", tz, err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+var (
+	ErrInsufficientHistory = errors.New("insufficient history")
+	ErrInvalidSimulations  = errors.New("invalid simulations")
+)
+
+const (
+	defaultSimulations = 2000
+	maxSimulations     = 20000
+	// historyMonths is how many complete months of ledger history the
+	// simulation draws its revenue and cost variance from.
+	historyMonths    = 12
+	minHistoryMonths = 3
+)
+
+// RunwaySimulationParams configure GetRunwayDistribution. A nil Seed picks one
+// from the clock; the seed used is returned so a run can be repeated.
+type RunwaySimulationParams struct {
+	Simulations       int
+	Seed              *int64
+	ExcludeRestricted bool
+}
+
+// GetRunwayDistribution runs a Monte Carlo simulation of monthly cash flow.
+// Each month's revenue and costs are drawn from normal distributions fitted to
+// recent complete months of the ledger; each retainer can churn with its
+// monthly probability, after which its amount is lost from revenue for the
+// rest of that path.
+func (s *forecastService) GetRunwayDistribution(agencyID string, params RunwaySimulationParams) (*models.RunwayDistributionView, error) {
+	if params.Simulations == 0 {
+		params.Simulations = defaultSimulations
+	}
+	if params.Simulations < 1 || params.Simulations > maxSimulations {
+		return nil, fmt.Errorf("%w: simulations must be 1-%d", ErrInvalidSimulations, maxSimulations)
+	}
+	seed := s.clock.Now().UnixNano()
+	if params.Seed != nil {
+		seed = *params.Seed
+	}
+
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	position, err := currentCashPosition(s.bankRepo, s.cashRepo, agencyID, ac.today())
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		return nil, ErrNoCashSnapshot
+	}
+
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	// Complete months only, and none before the agency existed
+	thisMonth := time.Date(ac.now.Year(), ac.now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := thisMonth.AddDate(0, -historyMonths, 0)
+	if agency != nil {
+		created := agency.CreatedAt.In(ac.now.Location())
+		if createdMonth := time.Date(created.Year(), created.Month(), 1, 0, 0, 0, 0, time.UTC); createdMonth.After(from) {
+			from = createdMonth
+		}
+	}
+	to := thisMonth.AddDate(0, 0, -1)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: need at least %d complete months of revenue and costs", ErrInsufficientHistory, minHistoryMonths)
+	}
+	totals, err := s.financeRepo.MonthlyTotals(agencyID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	totals = recordedHistory(totals)
+	recorded := 0
+	for _, t := range totals {
+		if t.HasEntries {
+			recorded++
+		}
+	}
+	if recorded < minHistoryMonths {
+		return nil, fmt.Errorf("%w: need at least %d complete months of revenue and costs, have %d", ErrInsufficientHistory, minHistoryMonths, recorded)
+	}
+
+	retainers, err := s.retainerRepo.ListActive(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	revenues := make([]float64, len(totals))
+	costs := make([]float64, len(totals))
+	for i, t := range totals {
+		revenues[i] = t.Revenue
+		costs[i] = t.Costs
+	}
+	model := runwayModel{
+		startingCash: position.total,
+		retainers:    retainers,
+	}
+	if params.ExcludeRestricted {
+		model.startingCash = position.available()
+	}
+	model.revenueMean, model.revenueStdDev = meanStdDev(revenues)
+	model.costMean, model.costStdDev = meanStdDev(costs)
+
+	runways := simulateRunway(model, rand.New(rand.NewSource(seed)), params.Simulations, maxForecastMonths)
+	sort.Float64s(runways)
+
+	return &models.RunwayDistributionView{
+		Seed:                 seed,
+		Simulations:          params.Simulations,
+		HorizonMonths:        maxForecastMonths,
+		StartingCash:         roundCents(model.startingCash),
+		ExcludesRestricted:   params.ExcludeRestricted,
+		HistoryMonths:        len(totals),
+		MonthlyRevenueMean:   roundCents(model.revenueMean),
+		MonthlyRevenueStdDev: roundCents(model.revenueStdDev),
+		MonthlyCostsMean:     roundCents(model.costMean),
+		MonthlyCostsStdDev:   roundCents(model.costStdDev),
+		RunwayP10Months:      roundTenths(percentile(runways, 0.10)),
+		RunwayP50Months:      roundTenths(percentile(runways, 0.50)),
+		RunwayP90Months:      roundTenths(percentile(runways, 0.90)),
+		ProbabilityBelowZero: models.BelowZeroProbabilityView{
+			Within3Months:  shareBelow(runways, 3),
+			Within6Months:  shareBelow(runways, 6),
+			Within12Months: shareBelow(runways, 12),
+		},
+	}, nil
+}
+
+// recordedHistory drops the months before the first one with ledger entries,
+// so an agency that started bookkeeping after it was created isn't fitted to
+// months of zeros.
+func recordedHistory(totals []repository.MonthlyTotalEntity) []repository.MonthlyTotalEntity {
+	for i, t := range totals {
+		if t.HasEntries {
+			return totals[i:]
+		}
+	}
+	return nil
+}
+
+type runwayModel struct {
+	startingCash  float64
+	revenueMean   float64
+	revenueStdDev float64
+	costMean      float64
+	costStdDev    float64
+	retainers     []repository.RetainerEntity
+}
+
+// simulateRunway returns each path's months until cash goes negative,
+// interpolated within the month it happens, or horizon if it never does. The
+// result depends only on the model and the random source.
+func simulateRunway(m runwayModel, rng *rand.Rand, simulations int, horizon int) []float64 {
+	runways := make([]float64, simulations)
+	churned := make([]bool, len(m.retainers))
+
+	for i := range runways {
+		for j := range churned {
+			churned[j] = false
+		}
+		cash := m.startingCash
+		if cash <= 0 {
+			continue
+		}
+		runways[i] = float64(horizon)
+
+		var lost float64
+		for month := 0; month < horizon; month++ {
+			for j, r := range m.retainers {
+				if !churned[j] && rng.Float64() < r.ChurnProbability {
+					churned[j] = true
+					lost += r.MonthlyAmount
+				}
+			}
+			revenue := math.Max(0, m.revenueMean+rng.NormFloat64()*m.revenueStdDev-lost)
+			costs := math.Max(0, m.costMean+rng.NormFloat64()*m.costStdDev)
+
+			next := cash + revenue - costs
+			if next < 0 {
+				runways[i] = float64(month) + cash/(cash-next)
+				break
+			}
+			cash = next
+		}
+	}
+	return runways
+}
+
+// meanStdDev returns the mean and sample standard deviation.
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)-1))
+}
+
+// percentile uses the nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// shareBelow is the fraction of runways shorter than months, to three places.
+func shareBelow(runways []float64, months float64) float64 {
+	var n int
+	for _, r := range runways {
+		if r < months {
+			n++
+		}
+	}
+	return float64(int(float64(n)/float64(len(runways))*1000)) / 1000
+}
+
+func roundTenths(v float64) float64 {
+	return float64(int(v*10)) / 10
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+func newSimulationService(t *testing.T, totals []repository.MonthlyTotalEntity) ForecastService {
+	settings := repository.DefaultAgencySettings("")
+	return NewForecastService(
+		&fakeAgencyRepo{createdAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		&fakeBankRepo{balances: []repository.AccountBalanceEntity{{AccountID: "account-1", BaseBalance: 40000}}},
+		nil,
+		&fakeFinanceRepo{monthlyTotals: totals},
+		&fakeRetainerRepo{retainers: []repository.RetainerEntity{
+			{ID: "retainer-1", MonthlyAmount: 6000, ChurnProbability: 0.1},
+			{ID: "retainer-2", MonthlyAmount: 3000, ChurnProbability: 0.05},
+		}},
+		nil,
+		nil,
+		nil,
+		&fakeSettingsRepo{settings: settings},
+		fixedAt(t, "2026-10-18T09:00:00Z"),
+	)
+}
+
+func monthlyTotals(values ...[2]float64) []repository.MonthlyTotalEntity {
+	totals := make([]repository.MonthlyTotalEntity, len(values))
+	for i, v := range values {
+		totals[i] = repository.MonthlyTotalEntity{
+			Month:      time.Date(2026, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01"),
+			Revenue:    v[0],
+			Costs:      v[1],
+			HasEntries: v[0] != 0 || v[1] != 0,
+		}
+	}
+	return totals
+}
+
+func TestRunwayDistributionIsReproducibleWithSeed(t *testing.T) {
+	totals := monthlyTotals(
+		[2]float64{20000, 24000},
+		[2]float64{18000, 25000},
+		[2]float64{22000, 23000},
+		[2]float64{19000, 26000},
+	)
+	seed := int64(42)
+	params := RunwaySimulationParams{Simulations: 500, Seed: &seed}
+
+	first, err := newSimulationService(t, totals).GetRunwayDistribution("agency-1", params)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	second, err := newSimulationService(t, totals).GetRunwayDistribution("agency-1", params)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+
+	if first.Seed != seed || second.Seed != seed {
+		t.Errorf("seeds = %d, %d, want %d", first.Seed, second.Seed, seed)
+	}
+	if first.RunwayP10Months != second.RunwayP10Months ||
+		first.RunwayP50Months != second.RunwayP50Months ||
+		first.RunwayP90Months != second.RunwayP90Months {
+		t.Errorf("percentiles differ: %v/%v/%v then %v/%v/%v",
+			first.RunwayP10Months, first.RunwayP50Months, first.RunwayP90Months,
+			second.RunwayP10Months, second.RunwayP50Months, second.RunwayP90Months)
+	}
+	if first.ProbabilityBelowZero != second.ProbabilityBelowZero {
+		t.Errorf("probabilities differ: %+v then %+v", first.ProbabilityBelowZero, second.ProbabilityBelowZero)
+	}
+	if first.RunwayP10Months > first.RunwayP50Months || first.RunwayP50Months > first.RunwayP90Months {
+		t.Errorf("percentiles out of order: %v/%v/%v", first.RunwayP10Months, first.RunwayP50Months, first.RunwayP90Months)
+	}
+}
+
+func TestRunwayDistributionRequiresRecordedMonths(t *testing.T) {
+	tests := []struct {
+		name   string
+		totals []repository.MonthlyTotalEntity
+		want   error
+	}{
+		{
+			name:   "empty ledger",
+			totals: monthlyTotals([2]float64{}, [2]float64{}, [2]float64{}, [2]float64{}, [2]float64{}, [2]float64{}),
+			want:   ErrInsufficientHistory,
+		},
+		{
+			name: "two recorded months",
+			totals: monthlyTotals(
+				[2]float64{}, [2]float64{}, [2]float64{}, [2]float64{},
+				[2]float64{20000, 24000}, [2]float64{18000, 25000},
+			),
+			want: ErrInsufficientHistory,
+		},
+		{
+			name: "three recorded months",
+			totals: monthlyTotals(
+				[2]float64{}, [2]float64{},
+				[2]float64{20000, 24000}, [2]float64{18000, 25000}, [2]float64{22000, 23000},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := newSimulationService(t, tt.totals).GetRunwayDistribution("agency-1", RunwaySimulationParams{Simulations: 10})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if err == nil && view.HistoryMonths != 3 {
+				t.Errorf("history months = %d, want 3", view.HistoryMonths)
+			}
+		})
+	}
+}
+
+func TestRunwayDistributionValidatesSimulations(t *testing.T) {
+	for _, n := range []int{-1, maxSimulations + 1} {
+		_, err := newSimulationService(t, nil).GetRunwayDistribution("agency-1", RunwaySimulationParams{Simulations: n})
+		if !errors.Is(err, ErrInvalidSimulations) {
+			t.Errorf("simulations %d: err = %v, want ErrInvalidSimulations", n, err)
+		}
+	}
+}
@@ -1,45 +1,84 @@
 package services
 
 import (
-	"time"
+	"math"
 
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
 	"github.com/agency-finance-reality/server/internal/models"
 	"github.com/agency-finance-reality/server/internal/repository"
 )
 
 type UtilizationService interface {
-	AddTimeEntry(agencyID string, clientID *string, hours float64) error
+	AddTimeEntry(agencyID string, clientID *string, date string, hours float64) error
 	GetUtilization(agencyID string) (*models.UtilizationView, error)
 }
 
 type utilizationService struct {
-	timeRepo repository.TimeEntryRepository
+	agencyRepo   repository.AgencyRepository
+	timeRepo     repository.TimeEntryRepository
+	settingsRepo repository.SettingsRepository
+	events       publisher
+	clock        clock.Clock
 }
 
-func NewUtilizationService(timeRepo repository.TimeEntryRepository) UtilizationService {
-	return &utilizationService{timeRepo: timeRepo}
+func NewUtilizationService(
+	agencyRepo repository.AgencyRepository,
+	timeRepo repository.TimeEntryRepository,
+	settingsRepo repository.SettingsRepository,
+	bus *events.Bus,
+	clk clock.Clock,
+) UtilizationService {
+	return &utilizationService{
+		agencyRepo:   agencyRepo,
+		timeRepo:     timeRepo,
+		settingsRepo: settingsRepo,
+		events:       publisher{bus: bus, clock: clk},
+		clock:        clk,
+	}
 }
 
-func (s *utilizationService) AddTimeEntry(agencyID string, clientID *string, hours float64) error {
-	return s.timeRepo.Add(agencyID, clientID, hours)
+func (s *utilizationService) AddTimeEntry(agencyID string, clientID *string, date string, hours float64) error {
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return err
+	}
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return err
+	}
+	entryDate, err := ac.entryDate(date, agency.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := s.timeRepo.Add(agencyID, clientID, entryDate, hours); err != nil {
+		return err
+	}
+	s.events.publish(events.TimeEntryCreated, agencyID, nil)
+	return nil
 }
 
 func (s *utilizationService) GetUtilization(agencyID string) (*models.UtilizationView, error) {
-	thirtyDaysAgo := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
-	used, err := s.timeRepo.SumHoursInRange(agencyID, thirtyDaysAgo)
+	ac, err := loadAgencyContext(s.settingsRepo, s.clock, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.timeRepo.SumHoursInRange(agencyID, ac.windowStart())
 	if err != nil {
 		return nil, err
 	}
 
-	capacity := 160.0 // Hardcoded capacity
+	// Capacity is configured per month; the window may be longer or shorter
+	capacity := ac.settings.MonthlyCapacityHours * ac.windowMonths()
 	view := &models.UtilizationView{
 		UsedHours:     used,
-		CapacityHours: capacity,
+		CapacityHours: math.Round(capacity*10) / 10,
 	}
 
 	if capacity > 0 {
 		percent := (used / capacity) * 100
-		view.UtilizationPercent = float64(int(percent*10)) / 10
+		view.UtilizationPercent = math.Round(percent*10) / 10
 	}
 
 	return view, nil
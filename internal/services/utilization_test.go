@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/agency-finance-reality/server/internal/repository"
+)
+
+func TestUtilizationScalesCapacityToLookbackWindow(t *testing.T) {
+	tests := []struct {
+		lookbackDays int
+		usedHours    float64
+		wantCapacity float64
+		wantPercent  float64
+	}{
+		// 160 monthly hours over 30.44 days
+		{30, 120, 157.7, 76.1},
+		{90, 360, 473.1, 76.1},
+		{7, 28, 36.8, 76.1},
+	}
+
+	for _, tt := range tests {
+		settings := repository.DefaultAgencySettings("")
+		settings.LookbackDays = tt.lookbackDays
+		svc := NewUtilizationService(nil, &fakeTimeRepo{hours: tt.usedHours}, &fakeSettingsRepo{settings: settings}, nil, fixedAt(t, "2026-10-18T09:00:00Z"))
+
+		view, err := svc.GetUtilization("agency-1")
+		if err != nil {
+			t.Fatalf("%d days: %v", tt.lookbackDays, err)
+		}
+		if view.CapacityHours != tt.wantCapacity {
+			t.Errorf("%d days: capacity = %v, want %v", tt.lookbackDays, view.CapacityHours, tt.wantCapacity)
+		}
+		if view.UtilizationPercent != tt.wantPercent {
+			t.Errorf("%d days: utilization = %v%%, want %v%%", tt.lookbackDays, view.UtilizationPercent, tt.wantPercent)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/agency-finance-reality/server/internal/clock"
+	"github.com/agency-finance-reality/server/internal/events"
+	"github.com/agency-finance-reality/server/internal/models"
+	"github.com/agency-finance-reality/server/internal/notify"
+	"github.com/agency-finance-reality/server/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+)
+
+// WebhookEventTypes are the events a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	events.RevenueCreated,
+	events.CostCreated,
+	events.CashSnapshotRecorded,
+	events.ClientCreated,
+	events.RetainerCreated,
+	events.ScoreStatusChanged,
+}
+
+const (
+	// A failed delivery is retried after 30s, 1m, 2m and so on, doubling,
+	// until it has been attempted maxWebhookAttempts times (about an hour).
+	maxWebhookAttempts = 8
+	webhookRetryBase   = 30 * time.Second
+	// webhookLease hides a delivery from the retry job while it's attempted.
+	webhookLease      = 5 * time.Minute
+	webhookRetryBatch = 100
+
+	defaultDeliveryHistory = 50
+	maxDeliveryHistory     = 500
+)
+
+type WebhookInput struct {
+	URL        string
+	EventTypes []string
+}
+
+// WebhookUpdate is a partial update; nil fields are left unchanged.
+type WebhookUpdate struct {
+	URL        *string
+	EventTypes []string
+	Enabled    *bool
+}
+
+type WebhookService interface {
+	ListWebhooks(agencyID string) ([]models.WebhookView, error)
+	CreateWebhook(agencyID string, input WebhookInput) (*models.CreatedWebhookView, error)
+	UpdateWebhook(agencyID string, id string, update WebhookUpdate) (*models.WebhookView, error)
+	DeleteWebhook(agencyID string, id string) error
+	ListDeliveries(agencyID string, webhookID string, limit int) ([]models.WebhookDeliveryView, error)
+	Redeliver(agencyID string, webhookID string, deliveryID string) (*models.WebhookDeliveryView, error)
+	Dispatch(e events.Event) error
+	RetryDue() error
+}
+
+type webhookService struct {
+	subscriptionRepo repository.WebhookSubscriptionRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	webhook          *notify.Webhook
+	clock            clock.Clock
+}
+
+func NewWebhookService(
+	subscriptionRepo repository.WebhookSubscriptionRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	webhook *notify.Webhook,
+	clk clock.Clock,
+) WebhookService {
+	return &webhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		webhook:          webhook,
+		clock:            clk,
+	}
+}
+
+func (s *webhookService) ListWebhooks(agencyID string) ([]models.WebhookView, error) {
+	subs, err := s.subscriptionRepo.List(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.WebhookView, len(subs))
+	for i, sub := range subs {
+		views[i] = toWebhookView(sub)
+	}
+	return views, nil
+}
+
+// CreateWebhook returns the new webhook's signing secret; it isn't shown
+// again.
+func (s *webhookService) CreateWebhook(agencyID string, input WebhookInput) (*models.CreatedWebhookView, error) {
+	if err := s.validate(input.URL, input.EventTypes); err != nil {
+		return nil, err
+	}
+	secret, err := notify.GenerateSigningSecret()
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.subscriptionRepo.Create(repository.WebhookSubscriptionEntity{
+		AgencyID:   agencyID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: input.EventTypes,
+		Enabled:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedWebhookView{
+		WebhookView: toWebhookView(*sub),
+		Secret:      secret,
+	}, nil
+}
+
+func (s *webhookService) UpdateWebhook(agencyID string, id string, update WebhookUpdate) (*models.WebhookView, error) {
+	sub, err := s.getSubscription(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		sub.URL = *update.URL
+	}
+	if update.EventTypes != nil {
+		sub.EventTypes = update.EventTypes
+	}
+	if update.Enabled != nil {
+		sub.Enabled = *update.Enabled
+	}
+	if err := s.validate(sub.URL, sub.EventTypes); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Update(*sub); err != nil {
+		return nil, err
+	}
+	view := toWebhookView(*sub)
+	return &view, nil
+}
+
+func (s *webhookService) validate(url string, eventTypes []string) error {
+	if err := s.webhook.Validate(url); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidWebhook, err)
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event type", ErrInvalidWebhook)
+	}
+	for _, t := range eventTypes {
+		if !isWebhookEventType(t) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, t)
+		}
+	}
+	return nil
+}
+
+func isWebhookEventType(t string) bool {
+	for _, known := range WebhookEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteWebhook removes the webhook along with its delivery log.
+func (s *webhookService) DeleteWebhook(agencyID string, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrWebhookNotFound
+	}
+	deleted, err := s.subscriptionRepo.Delete(agencyID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the webhook's most recent deliveries, 50 unless
+// limit says otherwise.
+func (s *webhookService) ListDeliveries(agencyID string, webhookID string, limit int) ([]models.WebhookDeliveryView, error) {
+	if limit == 0 {
+		limit = defaultDeliveryHistory
+	}
+	if limit < 1 || limit > maxDeliveryHistory {
+		return nil, fmt.Errorf("%w: limit must be 1-%d", ErrInvalidDateRange, maxDeliveryHistory)
+	}
+	sub, err := s.getSubscription(agencyID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.deliveryRepo.List(sub.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]models.WebhookDeliveryView, len(deliveries))
+	for i, d := range deliveries {
+		views[i] = toWebhookDeliveryView(d)
+	}
+	return views, nil
+}
+
+// Redeliver sends an earlier delivery's event again as a new delivery, once,
+// and returns its outcome. It is retried like any other if it fails.
+func (s *webhookService) Redeliver(agencyID string, webhookID string, deliveryID string) (*models.WebhookDeliveryView, error) {
+	sub, err := s.getSubscription(agencyID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	original, err := s.deliveryRepo.Get(agencyID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.SubscriptionID != sub.ID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	d, err := s.createDelivery(sub, original.EventID, original.EventType, original.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempt(sub, d); err != nil {
+		return nil, err
+	}
+	view := toWebhookDeliveryView(*d)
+	return &view, nil
+}
+
+// Dispatch sends an event to every enabled webhook subscribed to it. It is
+// subscribed to the event bus; failed deliveries are left for RetryDue.
+func (s *webhookService) Dispatch(e events.Event) error {
+	if !isWebhookEventType(e.Type) {
+		return nil
+	}
+	subs, err := s.subscriptionRepo.ListForEvent(e.AgencyID, e.Type)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	eventID := uuid.New().String()
+	payload, err := json.Marshal(models.WebhookEventView{
+		ID:         eventID,
+		Type:       e.Type,
+		AgencyID:   e.AgencyID,
+		OccurredAt: e.OccurredAt,
+		Data:       e.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed []error
+	for i := range subs {
+		d, err := s.createDelivery(&subs[i], eventID, e.Type, payload)
+		if err == nil {
+			err = s.attempt(&subs[i], d)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Errorf("webhook %s: %w", subs[i].ID, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// RetryDue attempts pending deliveries whose retry time has come. It runs
+// from the scheduler.
+func (s *webhookService) RetryDue() error {
+	now := s.clock.Now()
+	due, err := s.deliveryRepo.ClaimDue(now, now.Add(webhookLease), webhookRetryBatch)
+	if err != nil {
+		return err
+	}
+
+	var failed []error
+	for i := range due {
+		d := &due[i]
+		sub, err := s.subscriptionRepo.Get(d.AgencyID, d.SubscriptionID)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		if sub == nil || !sub.Enabled {
+			// Deliveries of a disabled webhook are abandoned, not held back
+			reason := "webhook disabled"
+			d.Status = repository.DeliveryFailed
+			d.NextAttemptAt = nil
+			d.LastError = &reason
+			err = s.deliveryRepo.RecordAttempt(*d)
+		} else {
+			err = s.attempt(sub, d)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Errorf("delivery %s: %w", d.ID, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// createDelivery records a pending delivery leased to the caller, who is
+// expected to attempt it straight away.
+func (s *webhookService) createDelivery(sub *repository.WebhookSubscriptionEntity, eventID string, eventType string, payload []byte) (*repository.WebhookDeliveryEntity, error) {
+	lease := s.clock.Now().Add(webhookLease)
+	return s.deliveryRepo.Create(repository.WebhookDeliveryEntity{
+		SubscriptionID: sub.ID,
+		AgencyID:       sub.AgencyID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         repository.DeliveryPending,
+		NextAttemptAt:  &lease,
+	})
+}
+
+// attempt posts the delivery, signed with the subscription's secret, and
+// records the outcome: delivered, scheduled for a retry, or failed for good.
+// Only a failure to record it is returned.
+func (s *webhookService) attempt(sub *repository.WebhookSubscriptionEntity, d *repository.WebhookDeliveryEntity) error {
+	now := s.clock.Now()
+	header := http.Header{}
+	header.Set("X-Webhook-Id", d.EventID)
+	header.Set("X-Webhook-Event", d.EventType)
+	header.Set("X-Webhook-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	header.Set("X-Webhook-Signature", "sha256="+notify.Sign(sub.Secret, now.Unix(), d.Payload))
+
+	code, err := s.webhook.Post(sub.URL, d.Payload, header)
+	d.Attempts++
+	d.LastStatusCode = nil
+	if code != 0 {
+		d.LastStatusCode = &code
+	}
+	if err == nil {
+		d.Status = repository.DeliveryDelivered
+		d.NextAttemptAt = nil
+		d.LastError = nil
+		d.DeliveredAt = &now
+	} else {
+		reason := err.Error()
+		d.LastError = &reason
+		if d.Attempts >= maxWebhookAttempts {
+			d.Status = repository.DeliveryFailed
+			d.NextAttemptAt = nil
+		} else {
+			next := now.Add(webhookRetryBase << (d.Attempts - 1))
+			d.NextAttemptAt = &next
+		}
+	}
+	return s.deliveryRepo.RecordAttempt(*d)
+}
+
+func (s *webhookService) getSubscription(agencyID string, id string) (*repository.WebhookSubscriptionEntity, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	sub, err := s.subscriptionRepo.Get(agencyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+func toWebhookView(sub repository.WebhookSubscriptionEntity) models.WebhookView {
+	return models.WebhookView{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Enabled:    sub.Enabled,
+		CreatedAt:  sub.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryView(d repository.WebhookDeliveryEntity) models.WebhookDeliveryView {
+	return models.WebhookDeliveryView{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+  id UUID PRIMARY KEY,
+  founder_id UUID NOT NULL REFERENCES founders(id),
+  name TEXT NOT NULL,
+  token_prefix TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scope TEXT NOT NULL CHECK (scope IN ('read', 'read_write')),
+  expires_at TIMESTAMP NULL,
+  last_used_at TIMESTAMP NULL,
+  revoked_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_founder ON api_tokens (founder_id);
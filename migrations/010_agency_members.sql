@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS agency_members (
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  founder_id UUID NOT NULL REFERENCES founders(id),
+  role TEXT NOT NULL CHECK (role IN ('owner', 'finance', 'viewer')),
+  created_at TIMESTAMP DEFAULT now(),
+  PRIMARY KEY (agency_id, founder_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_agency_members_founder ON agency_members (founder_id);
+
+CREATE TABLE IF NOT EXISTS agency_invitations (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  email TEXT NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('owner', 'finance', 'viewer')),
+  invited_by UUID NOT NULL REFERENCES founders(id),
+  accepted_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agency_invitations_pending ON agency_invitations (agency_id, lower(email)) WHERE accepted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_agency_invitations_email ON agency_invitations (lower(email)) WHERE accepted_at IS NULL;
+
+-- Existing single-founder agencies: the owner becomes the first member
+INSERT INTO agency_members (agency_id, founder_id, role)
+SELECT a.id, a.owner_user_id, 'owner'
+FROM agencies a
+JOIN founders f ON f.id = a.owner_user_id
+ON CONFLICT (agency_id, founder_id) DO NOTHING;
@@ -0,0 +1,9 @@
+-- Disambiguate agencies that were created twice by accident before the constraint existed
+UPDATE agencies a SET name = a.name || ' (' || d.rn || ')'
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY owner_user_id, lower(name) ORDER BY created_at) AS rn
+  FROM agencies
+) d
+WHERE a.id = d.id AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agencies_owner_name ON agencies (owner_user_id, lower(name));
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS agency_settings (
+  agency_id UUID PRIMARY KEY REFERENCES agencies(id),
+  timezone TEXT NOT NULL DEFAULT 'UTC',
+  fiscal_year_start_month INT NOT NULL DEFAULT 1 CHECK (fiscal_year_start_month BETWEEN 1 AND 12),
+  lookback_days INT NOT NULL DEFAULT 30 CHECK (lookback_days > 0),
+  monthly_capacity_hours NUMERIC NOT NULL DEFAULT 160 CHECK (monthly_capacity_hours > 0),
+  updated_at TIMESTAMP DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE agency_settings ADD COLUMN IF NOT EXISTS future_entry_tolerance_days INT NOT NULL DEFAULT 0 CHECK (future_entry_tolerance_days >= 0);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS cash_snapshot_revisions (
+  id UUID PRIMARY KEY,
+  snapshot_id UUID NOT NULL REFERENCES daily_cash_snapshots(id) ON DELETE CASCADE,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  date DATE NOT NULL,
+  previous_balance NUMERIC NOT NULL,
+  new_balance NUMERIC NOT NULL,
+  changed_by UUID NULL REFERENCES founders(id),
+  changed_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cash_snapshot_revisions_agency_date ON cash_snapshot_revisions (agency_id, date);
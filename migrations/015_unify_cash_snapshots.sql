@@ -0,0 +1,9 @@
+-- Opening balances used to be written to the legacy cash_snapshots table, which nothing reads.
+-- Move them into daily_cash_snapshots; a balance already recorded for the same day wins.
+INSERT INTO daily_cash_snapshots (id, agency_id, date, cash_balance, created_at)
+SELECT cs.id, cs.agency_id, cs.date, cs.cash_balance, cs.created_at
+FROM cash_snapshots cs
+JOIN agencies a ON a.id = cs.agency_id
+ON CONFLICT (agency_id, date) DO NOTHING;
+
+DROP TABLE IF EXISTS cash_snapshots;
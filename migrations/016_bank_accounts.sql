@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS bank_accounts (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  name TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  restricted BOOLEAN NOT NULL DEFAULT false,
+  archived_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS bank_account_snapshots (
+  id UUID PRIMARY KEY,
+  account_id UUID NOT NULL REFERENCES bank_accounts(id),
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  date DATE NOT NULL,
+  balance NUMERIC NOT NULL,
+  exchange_rate NUMERIC NOT NULL DEFAULT 1 CHECK (exchange_rate > 0),
+  created_at TIMESTAMP DEFAULT now(),
+  UNIQUE (account_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_account_snapshots_agency_date ON bank_account_snapshots (agency_id, date);
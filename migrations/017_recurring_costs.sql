@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS recurring_costs (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  amount NUMERIC NOT NULL CHECK (amount > 0),
+  label TEXT NOT NULL,
+  category TEXT NOT NULL DEFAULT 'other' CHECK (category IN ('people', 'tools', 'other')),
+  cadence TEXT NOT NULL CHECK (cadence IN ('weekly', 'monthly', 'annual')),
+  start_date DATE NOT NULL,
+  end_date DATE NULL,
+  -- Last date the scheduler has posted up to; NULL until the first run
+  posted_through DATE NULL,
+  archived_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+ALTER TABLE daily_costs ADD COLUMN IF NOT EXISTS recurring_cost_id UUID NULL REFERENCES recurring_costs(id);
+
+-- One posting per template per date; NULLs (manual costs) never collide
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_costs_recurring_date ON daily_costs (recurring_cost_id, date);
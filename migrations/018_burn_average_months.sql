@@ -0,0 +1 @@
+ALTER TABLE agency_settings ADD COLUMN IF NOT EXISTS burn_average_months INT NOT NULL DEFAULT 3 CHECK (burn_average_months IN (1, 3, 6));
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS planned_cash_items (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  date DATE NOT NULL,
+  amount NUMERIC NOT NULL CHECK (amount > 0),
+  direction TEXT NOT NULL CHECK (direction IN ('inflow', 'outflow')),
+  label TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_planned_cash_items_agency_date ON planned_cash_items (agency_id, date);
+
+ALTER TABLE agency_settings ADD COLUMN IF NOT EXISTS cash_safety_floor NUMERIC NOT NULL DEFAULT 0 CHECK (cash_safety_floor >= 0);
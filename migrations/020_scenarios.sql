@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS scenarios (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created_by UUID NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS scenario_adjustments (
+  id UUID PRIMARY KEY,
+  scenario_id UUID NOT NULL REFERENCES scenarios(id) ON DELETE CASCADE,
+  position INT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('add_hire', 'lose_client', 'add_retainer', 'cut_cost')),
+  label TEXT NOT NULL DEFAULT '',
+  amount NUMERIC NULL,
+  capacity_hours NUMERIC NULL,
+  client_id UUID NULL REFERENCES clients(id),
+  recurring_cost_id UUID NULL REFERENCES recurring_costs(id),
+  start_date DATE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scenario_adjustments_scenario ON scenario_adjustments (scenario_id, position);
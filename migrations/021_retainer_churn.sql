@@ -0,0 +1,2 @@
+-- Estimated probability that a retainer is lost in any given month
+ALTER TABLE retainers ADD COLUMN IF NOT EXISTS churn_probability NUMERIC NOT NULL DEFAULT 0 CHECK (churn_probability >= 0 AND churn_probability <= 1);
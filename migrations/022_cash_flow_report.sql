@@ -0,0 +1,31 @@
+ALTER TABLE retainers ADD COLUMN IF NOT EXISTS billing_day INT NOT NULL DEFAULT 1 CHECK (billing_day BETWEEN 1 AND 28);
+
+CREATE TABLE IF NOT EXISTS invoices (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  client_id UUID REFERENCES clients(id),
+  number TEXT NOT NULL,
+  amount NUMERIC NOT NULL CHECK (amount > 0),
+  due_date DATE NOT NULL,
+  paid_on DATE,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_agency_outstanding ON invoices (agency_id, due_date) WHERE paid_on IS NULL;
+
+-- One row per agency week (Monday start) holding the last forecast made
+-- before the week began and any actuals recorded afterwards.
+CREATE TABLE IF NOT EXISTS cash_flow_weeks (
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  week_start DATE NOT NULL,
+  forecast_inflows NUMERIC,
+  forecast_outflows NUMERIC,
+  forecast_closing NUMERIC,
+  forecast_at TIMESTAMP,
+  actual_inflows NUMERIC,
+  actual_outflows NUMERIC,
+  actual_closing NUMERIC,
+  actuals_recorded_by UUID,
+  actuals_recorded_at TIMESTAMP,
+  PRIMARY KEY (agency_id, week_start)
+);
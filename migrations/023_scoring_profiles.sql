@@ -0,0 +1,9 @@
+-- A NULL profile means the built-in defaults. The row is kept on reset so the
+-- version keeps counting up and a version tag never names two profiles.
+CREATE TABLE IF NOT EXISTS scoring_profiles (
+  agency_id UUID PRIMARY KEY REFERENCES agencies(id),
+  profile JSONB,
+  version INT NOT NULL DEFAULT 0,
+  updated_by UUID,
+  updated_at TIMESTAMP DEFAULT now()
+);
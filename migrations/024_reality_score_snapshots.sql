@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reality_score_snapshots (
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  date DATE NOT NULL,
+  score INT NOT NULL,
+  status TEXT NOT NULL,
+  primary_risk TEXT NOT NULL,
+  profile_version TEXT NOT NULL,
+  retainer_safety INT NOT NULL,
+  runway INT NOT NULL,
+  client_concentration INT NOT NULL,
+  profitability INT NOT NULL,
+  capacity_pressure INT NOT NULL,
+  inputs JSONB NOT NULL,
+  created_at TIMESTAMP DEFAULT now(),
+  PRIMARY KEY (agency_id, date)
+);
@@ -0,0 +1,38 @@
+-- Rules watch one reality score measurement against a lower and/or upper
+-- bound. breached_since is set when an alert fires and cleared once the value
+-- is back within bounds, so a breach alerts once; last_fired_at enforces the
+-- cooldown between alerts.
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  metric TEXT NOT NULL,
+  min_value NUMERIC,
+  max_value NUMERIC,
+  channel TEXT NOT NULL,
+  target TEXT NOT NULL,
+  cooldown_minutes INT NOT NULL DEFAULT 1440 CHECK (cooldown_minutes >= 0),
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  breached_since TIMESTAMP,
+  last_fired_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now(),
+  CHECK (min_value IS NOT NULL OR max_value IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_agency ON alert_rules (agency_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  rule_id UUID REFERENCES alert_rules(id) ON DELETE SET NULL,
+  metric TEXT NOT NULL,
+  value NUMERIC NOT NULL,
+  min_value NUMERIC,
+  max_value NUMERIC,
+  channel TEXT NOT NULL,
+  target TEXT NOT NULL,
+  fired_at TIMESTAMP NOT NULL,
+  delivered_at TIMESTAMP,
+  delivery_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_agency_fired ON alerts (agency_id, fired_at DESC);
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id UUID PRIMARY KEY,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_agency ON webhook_subscriptions (agency_id);
+
+-- One row per event sent to a subscription. Pending deliveries are retried
+-- from next_attempt_at until they succeed or run out of attempts.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID PRIMARY KEY,
+  subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  agency_id UUID NOT NULL REFERENCES agencies(id),
+  event_id UUID NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP,
+  last_status_code INT,
+  last_error TEXT,
+  delivered_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- The last reality score status seen for each agency, so changes can be
+-- announced once.
+CREATE TABLE IF NOT EXISTS reality_score_statuses (
+  agency_id UUID PRIMARY KEY REFERENCES agencies(id),
+  status TEXT NOT NULL,
+  changed_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,4 @@
+-- An alert whose delivery failed is retried rather than fired again.
+-- next_attempt_at spaces the retries out and stops concurrent evaluations
+-- sending the same alert twice.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP;